@@ -0,0 +1,142 @@
+package event
+
+import (
+	"sort"
+	"sync"
+)
+
+// SubscribeSpec describes a subscription of SubscribeBatch.
+type SubscribeSpec struct {
+	EventType Event       // The event type to subscribe to, nil for all events
+	Priority  int         // The priority of the subscriber
+	Fn        HandlerFunc // The handler func
+}
+
+// SubscribeBatch subscribes all specs under a single lock acquisition with a single sort per
+// affected event type, e.g. to register many handlers at startup. Concurrent fires observe either
+// none or all of the subscriptions of a type. Subscribers of equal priority keep the order of specs.
+// The returned func unsubscribes all of them at once. See Manager.Subscribe for more details.
+//
+// Managers not created by New subscribe each spec separately.
+func SubscribeBatch(mgr Subscriber, specs ...SubscribeSpec) (unsubscribeAll func()) {
+	m, ok := asManager(mgr)
+	if !ok {
+		unsubs := make([]func(), len(specs))
+		for i, spec := range specs {
+			unsubs[i] = mgr.Subscribe(spec.EventType, spec.Priority, spec.Fn)
+		}
+		return Merge(unsubs...)
+	}
+
+	types := make([]Type, len(specs))
+	subs := make([]*subscriber, len(specs))
+	for i, spec := range specs {
+		types[i] = m.typeOf(spec.EventType)
+		if m.noWildcard && types[i] == wildcardKey {
+			panic("event: wildcard subscribers are disabled by WithWildcardDisabled")
+		}
+		subs[i] = &subscriber{
+			priority: spec.Priority,
+			fn:       spec.Fn,
+			handler:  spec.Fn,
+		}
+		if m.strictTypes {
+			subs[i].expected = typeOf(spec.EventType)
+		}
+	}
+
+	counts := m.insertBatch(types, subs)
+	for _, eventType := range sortedTypes(counts) {
+		m.subscriberChanged(eventType, counts[eventType])
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			counts := m.removeBatch(types, subs)
+			for _, eventType := range sortedTypes(counts) {
+				m.subscriberChanged(eventType, counts[eventType])
+			}
+		})
+	}
+}
+
+// SubscribeAnyOf subscribes a handler to each of the event types with a priority, e.g. to handle a
+// closed set of related events by one handler, and returns a single func unsubscribing all of them.
+// Events of other types are not passed to the handler. The types are subscribed like by SubscribeBatch,
+// so a type listed twice calls the handler twice. See Manager.Subscribe for more details.
+func SubscribeAnyOf(mgr Subscriber, priority int, fn HandlerFunc, types ...Event) (unsubscribe func()) {
+	specs := make([]SubscribeSpec, len(types))
+	for i, t := range types {
+		specs[i] = SubscribeSpec{EventType: t, Priority: priority, Fn: fn}
+	}
+	return SubscribeBatch(mgr, specs...)
+}
+
+// insertBatch adds subs[i] to the subscriber list of types[i] and returns
+// the new number of subscribers of each affected type.
+func (m *manager) insertBatch(types []Type, subs []*subscriber) map[Type]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	added := make(map[Type][]*subscriber)
+	for i, sub := range subs {
+		if m.detectDuplicates {
+			m.warnDuplicate(types[i], sub)
+		}
+		m.subSeq++
+		sub.seq, sub.eventType = m.subSeq, types[i]
+		added[types[i]] = append(added[types[i]], sub)
+	}
+	m.subscribed.Add(int64(len(subs)))
+
+	counts := make(map[Type]int, len(added))
+	for eventType, add := range added {
+		list := m.list(eventType)
+		isNew := list == nil
+		if isNew {
+			list = new(subscriberList)
+		}
+		// The old subscribers are sorted and precede the new ones,
+		// so a stable sort keeps the order of subscription for equal priorities.
+		old := list.load()
+		merged := make([]*subscriber, 0, len(old)+len(add))
+		merged = append(append(merged, old...), add...)
+		sort.SliceStable(merged, func(i, j int) bool {
+			return merged[i].before(merged[j])
+		})
+		list.store(m.ordered(eventType, merged))
+		if isNew {
+			m.subscribers.set(eventType, list)
+		}
+		counts[eventType] = len(merged)
+	}
+	return counts
+}
+
+// removeBatch removes subs[i] from the subscriber list of types[i] and returns
+// the new number of subscribers of each type a subscriber was removed from.
+func (m *manager) removeBatch(types []Type, subs []*subscriber) map[Type]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[Type]int)
+	for i, sub := range subs {
+		if count, ok := m.removeLocked(types[i], sub); ok {
+			counts[types[i]] = count
+		}
+	}
+	return counts
+}
+
+// sortedTypes returns the keys of counts in a deterministic order.
+func sortedTypes(counts map[Type]int) []Type {
+	types := make([]Type, 0, len(counts))
+	for eventType := range counts {
+		types = append(types, eventType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+	return types
+}
@@ -0,0 +1,68 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeBatch(t *testing.T) {
+	var changes []SubscriberChanged
+	m := New(WithLifecycleEvents(true))
+	Subscribe(m, 0, func(e *SubscriberChanged) { changes = append(changes, *e) })
+
+	var calls []string
+	Subscribe(m, 1, func(*myEvent) { calls = append(calls, "existing") })
+	unsubscribe := SubscribeBatch(m,
+		SubscribeSpec{EventType: &myEvent{}, Priority: 0, Fn: func(Event) { calls = append(calls, "a") }},
+		SubscribeSpec{EventType: &myEvent{}, Priority: 2, Fn: func(Event) { calls = append(calls, "b") }},
+		SubscribeSpec{EventType: &myEvent{}, Priority: 1, Fn: func(Event) { calls = append(calls, "c") }},
+		SubscribeSpec{EventType: &baseEvent{}, Priority: 0, Fn: func(Event) { calls = append(calls, "base") }},
+	)
+	m.Fire(&myEvent{})
+	m.Fire(&baseEvent{})
+	require.Equal(t, []string{"b", "existing", "c", "a", "base"}, calls)
+
+	// One change per affected type
+	require.Equal(t, []SubscriberChanged{
+		{Type: KeyOf[*myEvent](), Count: 1},
+		{Type: KeyOf[*baseEvent](), Count: 1},
+		{Type: KeyOf[*myEvent](), Count: 4},
+	}, changes)
+
+	changes = nil
+	unsubscribe()
+	unsubscribe()
+	require.Equal(t, []SubscriberChanged{
+		{Type: KeyOf[*baseEvent](), Count: 0},
+		{Type: KeyOf[*myEvent](), Count: 1},
+	}, changes)
+	require.True(t, m.HasSubscriber(&myEvent{}))
+	require.False(t, m.HasSubscriber(&baseEvent{}))
+}
+
+func TestSubscribeBatchNop(t *testing.T) {
+	unsubscribe := SubscribeBatch(Nop, SubscribeSpec{EventType: &myEvent{}, Fn: func(Event) {}})
+	unsubscribe()
+}
+
+func TestSubscribeAnyOf(t *testing.T) {
+	m := New()
+	var seen []Event
+	unsubscribe := SubscribeAnyOf(m, 0, func(e Event) { seen = append(seen, e) },
+		&myEvent{}, KeyOf[*baseEvent]())
+	m.Fire(&myEvent{s: "a"})
+	m.Fire(&baseEvent{})
+	m.Fire(myEvent{s: "value"})
+	m.Fire(&SubscriberChanged{})
+	require.Equal(t, []Event{&myEvent{s: "a"}, &baseEvent{}}, seen)
+
+	unsubscribe()
+	m.Fire(&myEvent{})
+	m.Fire(&baseEvent{})
+	require.Len(t, seen, 2)
+	require.False(t, m.HasSubscriber())
+
+	unsubscribe = SubscribeAnyOf(Nop, 0, func(Event) {}, &myEvent{})
+	unsubscribe()
+}
@@ -0,0 +1,46 @@
+package event
+
+import "time"
+
+// Clock is the source of time of a manager, used for stats, handler tracking, slow handler detection
+// and timers of time-based features like WithCoalesce and Request timeouts.
+// It must be safe for concurrent use.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls f in its own goroutine after d elapsed, unless stopped before.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer started by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the timer from firing and reports whether it stopped it.
+	Stop() bool
+}
+
+// realClock is the Clock of real time.
+type realClock struct{}
+
+func (realClock) Now() time.Time                            { return time.Now() }
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// WithClock returns a ManagerOption that sets the clock of the manager, e.g. a fake clock
+// like eventtest.FakeClock to test time-based features deterministically. Default is real time.
+func WithClock(c Clock) ManagerOption {
+	return func(m *manager) {
+		m.clock = c
+	}
+}
+
+// since returns the time elapsed since t by the clock of the manager.
+func (m *manager) since(t time.Time) time.Duration {
+	return m.clock.Now().Sub(t)
+}
+
+// clockOf returns the clock of mgr if it was created by New and real time otherwise.
+func clockOf(mgr any) Clock {
+	if m, ok := asManager(mgr); ok {
+		return m.clock
+	}
+	return realClock{}
+}
@@ -0,0 +1,44 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClosed is returned by Publisher.FireErr for fires after Manager.Close with PostCloseError.
+var ErrClosed = errors.New("event manager closed")
+
+// PostClosePolicy is the behavior of fires after Manager.Close, see WithPostCloseBehavior.
+type PostClosePolicy int
+
+// Post close policies.
+const (
+	// PostCloseDrop drops the events without calling subscribers and logs them at debug level (V(1)).
+	PostCloseDrop PostClosePolicy = iota
+	// PostClosePanic panics in the goroutine firing the event with an error wrapping ErrClosed.
+	PostClosePanic
+	// PostCloseError makes Publisher.FireErr return ErrClosed and drops events like PostCloseDrop.
+	PostCloseError
+)
+
+// WithPostCloseBehavior returns a ManagerOption that sets the behavior of fires after Manager.Close,
+// e.g. to detect events arriving during teardown. Default is PostCloseDrop.
+// The after handlers of FireParallel still run for dropped events, so callers waiting for them do not block.
+func WithPostCloseBehavior(policy PostClosePolicy) ManagerOption {
+	return func(m *manager) {
+		m.postClose = policy
+	}
+}
+
+// rejectClosed reports whether the event must not be dispatched since the manager is closed,
+// or panics with PostClosePanic.
+func (m *manager) rejectClosed(event Event) bool {
+	if !m.closed.Load() {
+		return false
+	}
+	if m.postClose == PostClosePanic {
+		panic(fmt.Errorf("%w: fired %s", ErrClosed, m.typeName(m.typeOf(event))))
+	}
+	m.log.V(1).Info("dropped event fired after close", "eventType", m.typeName(m.typeOf(event)))
+	return true
+}
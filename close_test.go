@@ -0,0 +1,61 @@
+package event
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostCloseBehavior(t *testing.T) {
+	subscribed := func(m Manager) *int {
+		var called int
+		Subscribe(m, 0, func(*myEvent) { called++ })
+		m.SubscribeTopic("a", 0, func(any) { called++ })
+		return &called
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		m := New()
+		called := subscribed(m)
+		require.NoError(t, m.Close())
+		m.Fire(&myEvent{})
+		m.FireTopic("a", nil)
+		require.NoError(t, m.FireErr(&myEvent{}))
+		after := make(chan struct{})
+		m.FireParallel(&myEvent{}, func(Event) { close(after) })
+		<-after // After handlers still run
+		require.Zero(t, *called)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		m := New(WithPostCloseBehavior(PostClosePanic))
+		called := subscribed(m)
+		m.Fire(&myEvent{})
+		require.NoError(t, m.Close())
+		for _, fire := range []func(){
+			func() { m.Fire(&myEvent{}) },
+			func() { m.FireParallel(&myEvent{}) },
+			func() { m.FireTopic("a", nil) },
+		} {
+			func() {
+				defer func() {
+					err, _ := recover().(error)
+					require.True(t, errors.Is(err, ErrClosed))
+				}()
+				fire()
+			}()
+		}
+		require.Equal(t, 1, *called)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m := New(WithPostCloseBehavior(PostCloseError))
+		called := subscribed(m)
+		require.NoError(t, m.FireErr(&myEvent{}))
+		require.NoError(t, m.Close())
+		require.ErrorIs(t, m.FireErr(&myEvent{}), ErrClosed)
+		m.Fire(&myEvent{})
+		require.Equal(t, 1, *called)
+	})
+}
@@ -0,0 +1,103 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// coalescer holds the most recent fire of a coalesced event type until its interval elapses.
+type coalescer struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending *pausedFire // Most recent fire not yet dispatched, nil if none
+	timer   Timer       // Flushes pending, nil if no fire is pending
+	closed  bool        // Dispatch fires immediately after Close
+}
+
+// WithCoalesce returns a ManagerOption that coalesces the fires of the event type t.
+// The first fire of t starts an interval, and when it elapses only the most recent event
+// fired within it is dispatched, so unlike WithDuplicateDetection the newest event is always delivered.
+//
+// The fires return before subscribers are called, and subscribers see an event up to interval
+// after it was fired, in the goroutine of a timer of the Clock. Coalesced events are dispatched in order of intervals,
+// but relative to fires of other types they may arrive late. Manager.Wait does not wait for pending events.
+// Close dispatches pending events before waiting for subscribers.
+func WithCoalesce(t Type, interval time.Duration) ManagerOption {
+	return func(m *manager) {
+		if m.coalescers == nil {
+			m.coalescers = make(map[Type]*coalescer)
+		}
+		m.coalescers[t] = &coalescer{interval: interval}
+	}
+}
+
+// coalesce holds the fire if its event type is coalesced and reports whether it did.
+func (m *manager) coalesce(event Event, eventType Type, opts *fireOptions) bool {
+	if opts != nil && opts.uncoalesced {
+		return false
+	}
+	c := m.coalescers[eventType]
+	if c == nil {
+		return false
+	}
+	f := &pausedFire{event: event}
+	if opts != nil {
+		f.opts = *opts
+	}
+	f.opts.uncoalesced = true
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	if c.pending == nil {
+		m.queued.Add(1)
+	}
+	c.pending = f
+	if c.timer == nil {
+		c.timer = m.clock.AfterFunc(c.interval, func() { m.flushCoalesced(c) })
+	}
+	return true
+}
+
+// flushCoalesced dispatches the pending fire of the coalescer, if any.
+func (m *manager) flushCoalesced(c *coalescer) {
+	c.mu.Lock()
+	f := c.pending
+	c.pending, c.timer = nil, nil
+	c.mu.Unlock()
+	if f == nil {
+		return
+	}
+	m.enter()
+	defer m.exit()
+	m.queued.Add(-1)
+	m.fire(f.event, &f.opts)
+}
+
+// closeCoalescers stops the timers of all coalescers and dispatches their pending fires.
+func (m *manager) closeCoalescers() {
+	for _, c := range m.coalescers {
+		c.mu.Lock()
+		c.closed = true
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		c.mu.Unlock()
+		m.flushCoalesced(c)
+	}
+}
+
+// resetCoalescers drops the pending fires of all coalescers.
+func (m *manager) resetCoalescers() {
+	for _, c := range m.coalescers {
+		c.mu.Lock()
+		if c.pending != nil {
+			m.queued.Add(-1)
+		}
+		c.pending = nil
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesce(t *testing.T) {
+	m := New(WithCoalesce(KeyOf[*myEvent](), 20*time.Millisecond))
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	Subscribe(m, 0, func(e *myEvent) {
+		mu.Lock()
+		got = append(got, e.s)
+		mu.Unlock()
+	})
+	var other int
+	Subscribe(m, 0, func(*baseEvent) { other++ })
+
+	for _, s := range []string{"a", "b", "c"} {
+		m.Fire(&myEvent{s: s})
+	}
+	m.Fire(&baseEvent{})
+	require.Equal(t, 1, other) // Not coalesced
+	mu.Lock()
+	require.Empty(t, got)
+	mu.Unlock()
+	require.Equal(t, 1, m.QueueDepth())
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 1
+	}, time.Second, time.Millisecond)
+	mu.Lock()
+	require.Equal(t, []string{"c"}, got)
+	mu.Unlock()
+	require.Zero(t, m.QueueDepth())
+
+	// Close flushes the pending event and later fires are dropped
+	m.Fire(&myEvent{s: "d"})
+	m.Fire(&myEvent{s: "e"})
+	require.NoError(t, m.Close())
+	m.Fire(&myEvent{s: "f"})
+	mu.Lock()
+	require.Equal(t, []string{"c", "e"}, got)
+	mu.Unlock()
+}
+
+func TestCoalesceReset(t *testing.T) {
+	m := New(WithCoalesce(KeyOf[*myEvent](), time.Hour))
+	var called int
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	m.Fire(&myEvent{})
+	m.Reset()
+	require.Zero(t, m.QueueDepth())
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	require.NoError(t, m.Close())
+	require.Zero(t, called)
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"context"
+	"errors"
+)
+
+type observersKey struct{}
+
+// WithContextObserver returns a copy of ctx with an observer that is called with every event
+// fired by FireCtx with the returned context or a context derived from it, e.g. to capture
+// all events of one request without subscribing globally.
+// Observers are called after all subscribers in the order they were added.
+func WithContextObserver(ctx context.Context, fn func(Event)) context.Context {
+	parent := contextObservers(ctx)
+	observers := make([]func(Event), len(parent), len(parent)+1)
+	copy(observers, parent)
+	return context.WithValue(ctx, observersKey{}, append(observers, fn))
+}
+
+// contextObservers returns the observers added to ctx by WithContextObserver.
+func contextObservers(ctx context.Context) []func(Event) {
+	observers, _ := ctx.Value(observersKey{}).([]func(Event))
+	return observers
+}
+
+// fireObservers calls the context observers of the fire.
+func (m *manager) fireObservers(event Event, opts *fireOptions) {
+	if opts == nil || opts.ctx == nil {
+		return
+	}
+	for _, fn := range contextObservers(opts.ctx) {
+		m.callHook("context observer", func(_ Type, e Event) { fn(e) }, m.typeOf(event), event)
+	}
+}
+
+// WithDeadlineEnforcement returns a ManagerOption that enables/disables skipping the subscribers of a
+// fire with a context, e.g. by FireCtx, once the deadline of the context passed, so no work is started
+// that is guaranteed to be too late. The deadline is checked before calling each subscriber and every
+// skipped subscriber is logged. Subscribers already running are not interrupted. Default is false.
+func WithDeadlineEnforcement(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.enforceDeadline = enabled
+	}
+}
+
+// deadlineExceeded reports whether the subscriber must be skipped since the deadline of the context
+// of the fire passed and logs the skipped subscriber.
+func (m *manager) deadlineExceeded(sub *subscriber, event Event, opts *fireOptions) bool {
+	if !m.enforceDeadline || opts == nil || opts.ctx == nil ||
+		!errors.Is(opts.ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	m.log.WithValues(sub.logValues()...).Info("skipped event subscriber after context deadline exceeded",
+		"eventType", m.typeName(m.typeOf(event)))
+	return true
+}
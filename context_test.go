@@ -0,0 +1,74 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextObserver(t *testing.T) {
+	m := New()
+	var order []string
+	Subscribe(m, 0, func(e *myEvent) {
+		order = append(order, "subscriber")
+		e.s = "mutated"
+	})
+
+	ctx := WithContextObserver(context.Background(), func(e Event) {
+		order = append(order, "observer1:"+e.(*myEvent).s)
+	})
+	child := WithContextObserver(ctx, func(e Event) {
+		order = append(order, "observer2")
+		panic("recovered")
+	})
+
+	m.FireCtx(child, &myEvent{})
+	require.Equal(t, []string{"subscriber", "observer1:mutated", "observer2"}, order)
+
+	// Observers do not persist beyond their context
+	order = nil
+	m.FireCtx(ctx, &myEvent{})
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"subscriber", "observer1:mutated", "subscriber"}, order)
+}
+
+func TestDeadlineEnforcement(t *testing.T) {
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	m := New(WithDeadlineEnforcement(true), WithLogger(log))
+	var called []int
+	for i := 3; i > 0; i-- {
+		i := i
+		Subscribe(m, i, func(*myEvent) {
+			called = append(called, i)
+			time.Sleep(20 * time.Millisecond)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	m.FireCtx(ctx, &myEvent{})
+	require.Equal(t, []int{3}, called)
+	require.Len(t, logs, 2)
+	require.Contains(t, logs[0], "context deadline exceeded")
+	require.Contains(t, logs[0], `"subscriberPriority"=2`)
+	require.Contains(t, logs[1], `"subscriberPriority"=1`)
+
+	// Fires without a context and canceled contexts are not affected
+	called = nil
+	m.Fire(&myEvent{})
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.FireCtx(canceled, &myEvent{})
+	require.Len(t, called, 6)
+
+	// Disabled by default
+	m = New()
+	called = nil
+	Subscribe(m, 0, func(*myEvent) { called = append(called, 0) })
+	m.FireCtx(ctx, &myEvent{})
+	require.Equal(t, []int{0}, called)
+}
@@ -0,0 +1,45 @@
+package event
+
+import "sync"
+
+// WithDispatchTrace returns a ManagerOption that calls fn after each fire with the event type and the
+// priorities of the subscribers in the order they were called, e.g. to debug unexpected handler order.
+// It covers wildcard subscribers and those reached by pointer and value unification or embedded matching,
+// but not the hooks and context observers. Concurrent fires report the order subscribers were started.
+// The order is only recorded if the option is set. The slice must not be retained after fn returns.
+func WithDispatchTrace(fn func(t Type, order []int)) ManagerOption {
+	return func(m *manager) {
+		m.dispatchTrace = fn
+	}
+}
+
+// dispatchOrder records the priorities of the subscribers called by a fire.
+type dispatchOrder struct {
+	mu         sync.Mutex // Protects following fields, needed for concurrent fires
+	priorities []int
+}
+
+// add records the call of a subscriber with the priority.
+func (o *dispatchOrder) add(priority int) {
+	o.mu.Lock()
+	o.priorities = append(o.priorities, priority)
+	o.mu.Unlock()
+}
+
+// withDispatchOrder returns a copy of the options recording the order of subscribers.
+func withDispatchOrder(opts *fireOptions) *fireOptions {
+	var o fireOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.order = new(dispatchOrder)
+	return &o
+}
+
+// callDispatchTrace calls the func of WithDispatchTrace, recovering any panic if enabled.
+func (m *manager) callDispatchTrace(eventType Type, order *dispatchOrder) {
+	order.mu.Lock()
+	priorities := order.priorities
+	order.mu.Unlock()
+	m.callHook("dispatch trace", func(t Type, _ Event) { m.dispatchTrace(t, priorities) }, eventType, nil)
+}
@@ -0,0 +1,38 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatchTrace(t *testing.T) {
+	var (
+		types  []Type
+		orders [][]int
+	)
+	m := New(WithDispatchTrace(func(typ Type, order []int) {
+		types = append(types, typ)
+		orders = append(orders, append([]int(nil), order...))
+	}))
+	Subscribe(m, 3, func(*myEvent) {})
+	SubscribeAll(m, 5, func(Event) {})
+	Subscribe(m, 1, func(*myEvent) {})
+	SubscribeAll(m, 2, func(Event) {})
+	Subscribe(m, 4, func(*myEvent) {})
+
+	m.Fire(&myEvent{})
+	m.Fire(&baseEvent{})
+	m.FireRange(&myEvent{}, 2, 3)
+	require.Equal(t, []Type{KeyOf[*myEvent](), KeyOf[*baseEvent](), KeyOf[*myEvent]()}, types)
+	require.Equal(t, [][]int{{5, 2, 4, 3, 1}, {5, 2}, {2, 3}}, orders)
+
+	types, orders = nil, nil
+	m = New(WithWildcardLast(true), WithDispatchTrace(func(typ Type, order []int) {
+		orders = append(orders, append([]int(nil), order...))
+	}))
+	SubscribeAll(m, 5, func(Event) {})
+	Subscribe(m, 1, func(*myEvent) {})
+	m.Fire(&myEvent{})
+	require.Equal(t, [][]int{{1, 5}}, orders)
+}
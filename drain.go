@@ -0,0 +1,71 @@
+package event
+
+import "strings"
+
+// drain collects the panics of unsubscribed subscribers during UnsubscribeAllAndWait.
+type drain struct {
+	subs map[*subscriber]struct{} // The unsubscribed subscribers by identity
+	errs []error                  // Recovered panics in order of recovering, protected by m.drainMu
+}
+
+// startDrain starts collecting the panics of the subscribers of the lists into d.
+func (m *manager) startDrain(d *drain, lists map[Type]*subscriberList) {
+	for _, list := range lists {
+		for _, sub := range list.load() {
+			d.subs[sub.identity()] = struct{}{}
+		}
+	}
+	m.drainMu.Lock()
+	if m.drains == nil {
+		m.drains = make(map[*drain]struct{})
+	}
+	m.drains[d] = struct{}{}
+	m.drainMu.Unlock()
+	m.draining.Add(1)
+}
+
+// stopDrain stops collecting panics for the drain.
+func (m *manager) stopDrain(d *drain) {
+	m.draining.Add(-1)
+	m.drainMu.Lock()
+	delete(m.drains, d)
+	m.drainMu.Unlock()
+}
+
+// drainPanic adds the panic of the subscriber to all drains waiting for it.
+func (m *manager) drainPanic(sub *subscriber, err *PanicError) {
+	m.drainMu.Lock()
+	defer m.drainMu.Unlock()
+	for d := range m.drains {
+		if _, ok := d.subs[sub.identity()]; ok {
+			d.errs = append(d.errs, err)
+		}
+	}
+}
+
+// err returns the collected panics joined, or nil if there are none.
+func (d *drain) err() error {
+	switch len(d.errs) {
+	case 0:
+		return nil
+	case 1:
+		return d.errs[0]
+	default:
+		return joinedError(append([]error(nil), d.errs...))
+	}
+}
+
+// joinedError is an error joining multiple errors like errors.Join of Go 1.20.
+type joinedError []error
+
+func (e joinedError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e joinedError) Unwrap() []error {
+	return e
+}
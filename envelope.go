@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// Envelope is the provenance of a fire recorded with WithEnvelope.
+type Envelope struct {
+	ID      string    // Unique id of the fire, a random UUID unless set by WithEnvelopeIDFunc
+	FiredAt time.Time // When the event was dispatched to the subscribers by the Clock of the manager
+	Source  string    // The source set by WithEnvelopeSource on the context of the fire, if any
+}
+
+type envelopeKey struct{}
+
+type envelopeSourceKey struct{}
+
+// WithEnvelope returns a ManagerOption that records an Envelope for every fire, which handlers
+// subscribed by SubscribeCtx retrieve from their context with EnvelopeFrom, e.g. for audit logs.
+// All subscribers of one fire see the same Envelope. Nested fires using that context get their own.
+func WithEnvelope() ManagerOption {
+	return func(m *manager) {
+		m.envelope = true
+	}
+}
+
+// WithEnvelopeIDFunc returns a ManagerOption that sets the func generating the ids of envelopes
+// recorded by WithEnvelope, e.g. for ULIDs. It must be safe for concurrent use. Default is a random UUID.
+func WithEnvelopeIDFunc(fn func() string) ManagerOption {
+	return func(m *manager) {
+		m.envelopeID = fn
+	}
+}
+
+// WithEnvelopeSource returns a copy of ctx with the source of envelopes recorded
+// for fires with the returned context or a context derived from it.
+func WithEnvelopeSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, envelopeSourceKey{}, source)
+}
+
+// EnvelopeFrom returns the Envelope of the fire of a handler's context, see WithEnvelope.
+func EnvelopeFrom(ctx context.Context) (Envelope, bool) {
+	env, ok := ctx.Value(envelopeKey{}).(Envelope)
+	return env, ok
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("event: generating envelope id: %w", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// withEnvelope returns a copy of the options with an Envelope added to the context.
+func (m *manager) withEnvelope(opts *fireOptions) *fireOptions {
+	var o fireOptions
+	if opts != nil {
+		o = *opts
+	}
+	ctx := o.context()
+	source, _ := ctx.Value(envelopeSourceKey{}).(string)
+	o.ctx = context.WithValue(ctx, envelopeKey{}, Envelope{
+		ID:      m.envelopeID(),
+		FiredAt: m.clock.Now(),
+		Source:  source,
+	})
+	return &o
+}
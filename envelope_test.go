@@ -0,0 +1,58 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	m := New(WithEnvelope())
+	var envs []Envelope
+	record := func(ctx context.Context, _ *myEvent) {
+		env, ok := EnvelopeFrom(ctx)
+		require.True(t, ok)
+		envs = append(envs, env)
+	}
+	SubscribeCtx(m, 1, record)
+	SubscribeCtx(m, 0, record)
+
+	m.FireCtx(WithEnvelopeSource(context.Background(), "api"), &myEvent{})
+	require.Len(t, envs, 2)
+	require.Equal(t, envs[0], envs[1])
+	require.Equal(t, "api", envs[0].Source)
+	require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), envs[0].ID)
+	require.False(t, envs[0].FiredAt.IsZero())
+
+	m.Fire(&myEvent{})
+	require.Len(t, envs, 4)
+	require.NotEqual(t, envs[0].ID, envs[2].ID)
+	require.Empty(t, envs[2].Source)
+
+	_, ok := EnvelopeFrom(context.Background())
+	require.False(t, ok)
+}
+
+func TestEnvelopeIDFunc(t *testing.T) {
+	var n int
+	m := New(WithEnvelope(), WithEnvelopeIDFunc(func() string { n++; return fmt.Sprint(n) }))
+	var ids []string
+	SubscribeCtx(m, 0, func(ctx context.Context, _ *myEvent) {
+		env, _ := EnvelopeFrom(ctx)
+		ids = append(ids, env.ID)
+	})
+	m.Fire(&myEvent{})
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"1", "2"}, ids)
+
+	// Without the option no envelope is recorded
+	m = New()
+	SubscribeCtx(m, 0, func(ctx context.Context, _ *myEvent) {
+		_, ok := EnvelopeFrom(ctx)
+		require.False(t, ok)
+	})
+	m.Fire(&myEvent{})
+}
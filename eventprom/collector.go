@@ -0,0 +1,139 @@
+// Package eventprom provides a Prometheus adapter for event manager metrics.
+package eventprom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/robinbraemer/event"
+)
+
+// OtherType is the event type label of events exceeding the type limit of a Collector.
+const OtherType = "other"
+
+// Collector is an event.MetricsRecorder recording fired events, fire durations and
+// subscriber panics labeled by event type as Prometheus metrics.
+// Wire it into a manager with event.WithMetrics.
+type Collector struct {
+	fired    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	panics   *prometheus.CounterVec
+
+	maxTypes int
+	mu       sync.RWMutex        // Protects following fields
+	types    map[string]struct{} // Event type labels in use
+}
+
+var _ event.MetricsRecorder = (*Collector)(nil)
+
+// Option is a Collector option for NewCollector.
+type Option func(*options)
+
+type options struct {
+	namespace string
+	maxTypes  int
+	buckets   []float64
+}
+
+// WithNamespace returns an Option that sets the namespace of the metric names.
+// Default is "event".
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithMaxTypes returns an Option that limits the number of distinct event type labels to n
+// to guard Prometheus against unbounded label cardinality, e.g. from dynamic type keys.
+// Events of further types are recorded with the OtherType label. Default is 100.
+func WithMaxTypes(n int) Option {
+	return func(o *options) {
+		o.maxTypes = n
+	}
+}
+
+// WithBuckets returns an Option that sets the buckets in seconds of the fire duration histogram.
+// Default is prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
+// NewCollector returns a new Collector with its metrics registered with reg.
+// If registering any metric fails, none of them stay registered.
+func NewCollector(reg prometheus.Registerer, opts ...Option) (*Collector, error) {
+	o := options{
+		namespace: "event",
+		maxTypes:  100,
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := &Collector{
+		fired: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "fired_total",
+			Help:      "Number of fired events.",
+		}, []string{"type"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "fire_duration_seconds",
+			Help:      "Duration of firing events to all subscribers.",
+			Buckets:   o.buckets,
+		}, []string{"type"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "subscriber_panics_total",
+			Help:      "Number of recovered subscriber panics.",
+		}, []string{"type", "subscriber"}),
+		maxTypes: o.maxTypes,
+		types:    make(map[string]struct{}),
+	}
+	cols := []prometheus.Collector{c.fired, c.duration, c.panics}
+	for i, col := range cols {
+		if err := reg.Register(col); err != nil {
+			// Unregister the earlier metrics so NewCollector can be retried with reg
+			for _, registered := range cols[:i] {
+				reg.Unregister(registered)
+			}
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// EventFired implements event.MetricsRecorder.
+func (c *Collector) EventFired(eventType string, d time.Duration) {
+	eventType = c.typeLabel(eventType)
+	c.fired.WithLabelValues(eventType).Inc()
+	c.duration.WithLabelValues(eventType).Observe(d.Seconds())
+}
+
+// SubscriberPanicked implements event.MetricsRecorder.
+func (c *Collector) SubscriberPanicked(eventType, subscriber string) {
+	c.panics.WithLabelValues(c.typeLabel(eventType), subscriber).Inc()
+}
+
+// typeLabel returns the label of the event type, which is OtherType if the type limit is exceeded.
+func (c *Collector) typeLabel(eventType string) string {
+	c.mu.RLock()
+	_, ok := c.types[eventType]
+	c.mu.RUnlock()
+	if ok {
+		return eventType
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok = c.types[eventType]; ok {
+		return eventType
+	}
+	if len(c.types) >= c.maxTypes {
+		return OtherType
+	}
+	c.types[eventType] = struct{}{}
+	return eventType
+}
@@ -0,0 +1,64 @@
+package eventprom
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/robinbraemer/event"
+)
+
+type (
+	eventA struct{}
+	eventB struct{}
+	eventC struct{}
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c, err := NewCollector(reg, WithMaxTypes(2))
+	require.NoError(t, err)
+
+	m := event.New(event.WithMetrics(c))
+	m.RegisterType("a", &eventA{})
+	event.SubscribeNamed(m, "broken", 0, func(*eventA) { panic("boom") })
+	m.Fire(&eventA{})
+	m.Fire(&eventA{})
+	m.Fire(&eventB{})
+	m.Fire(&eventC{}) // Exceeds the type limit
+
+	require.Equal(t, 2.0, testutil.ToFloat64(c.fired.WithLabelValues("a")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.fired.WithLabelValues("*eventprom.eventB")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.fired.WithLabelValues(OtherType)))
+	require.Equal(t, 2.0, testutil.ToFloat64(c.panics.WithLabelValues("a", "broken")))
+	require.Equal(t, 3, testutil.CollectAndCount(c.duration))
+
+	_, err = NewCollector(reg)
+	require.Error(t, err) // Already registered
+}
+
+// failingRegisterer fails to register the last of the Collector's metrics.
+type failingRegisterer struct {
+	prometheus.Registerer
+	n int // Number of Register calls
+}
+
+func (r *failingRegisterer) Register(c prometheus.Collector) error {
+	if r.n++; r.n == 3 {
+		return errors.New("register failed")
+	}
+	return r.Registerer.Register(c)
+}
+
+func TestCollectorRegisterFailure(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := NewCollector(&failingRegisterer{Registerer: reg})
+	require.EqualError(t, err, "register failed")
+
+	// The metrics registered before the failure were unregistered
+	_, err = NewCollector(reg)
+	require.NoError(t, err)
+}
@@ -0,0 +1,88 @@
+package eventtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robinbraemer/event"
+)
+
+// FakeClock is an event.Clock whose time only moves when advanced, e.g. to test
+// time-based features of a manager created with event.WithClock deterministically.
+// It is safe for concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex   // Protects following fields
+	now    time.Time    // The current time
+	timers []*fakeTimer // Pending timers
+}
+
+var _ event.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a new FakeClock at the time now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc calls f when the clock is advanced by at least d.
+// Unlike time.AfterFunc, f is called in the goroutine advancing the clock.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) event.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and calls the funcs of all timers due
+// in order of their time, with the clock set to the time of each timer.
+// Timers started by those funcs are called as well if due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].at.Before(c.timers[j].at)
+		})
+		if len(c.timers) == 0 || c.timers[0].at.After(end) {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		if t.at.After(c.now) {
+			c.now = t.at
+		}
+		c.mu.Unlock()
+		t.f()
+	}
+}
+
+// fakeTimer is a timer of a FakeClock.
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	f     func()
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, other := range t.clock.timers {
+		if other == t {
+			t.clock.timers = append(t.clock.timers[:i:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package eventtest
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robinbraemer/event"
+	"github.com/stretchr/testify/require"
+)
+
+type tick struct{ n int }
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	var calls []string
+	c.AfterFunc(2*time.Second, func() { calls = append(calls, "b") })
+	stopped := c.AfterFunc(time.Second, func() { calls = append(calls, "stopped") })
+	c.AfterFunc(time.Second, func() {
+		calls = append(calls, "a")
+		c.AfterFunc(time.Second, func() { calls = append(calls, "nested") })
+	})
+	require.True(t, stopped.Stop())
+	require.False(t, stopped.Stop())
+
+	c.Advance(time.Second)
+	require.Equal(t, []string{"a"}, calls)
+	require.Equal(t, start.Add(time.Second), c.Now())
+	c.Advance(5 * time.Second)
+	require.Equal(t, []string{"a", "b", "nested"}, calls)
+	require.Equal(t, start.Add(6*time.Second), c.Now())
+}
+
+func TestFakeClockManager(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	m := event.New(event.WithClock(c), event.WithCoalesce(event.KeyOf[*tick](), time.Second))
+	var got []int
+	event.Subscribe(m, 0, func(e *tick) { got = append(got, e.n) })
+
+	m.Fire(&tick{n: 1})
+	m.Fire(&tick{n: 2})
+	c.Advance(999 * time.Millisecond)
+	require.Empty(t, got)
+	c.Advance(time.Millisecond)
+	require.Equal(t, []int{2}, got)
+
+	_, last := m.Stats(&tick{})
+	require.True(t, start.Add(time.Second).Equal(last))
+}
+
+func TestFakeClockRetry(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	m := event.New(event.WithClock(c))
+	var calls atomic.Int32
+	var retried []time.Time
+	event.SubscribeRetry(m, 0, 2, time.Hour, func(*tick) error {
+		if calls.Add(1) > 1 {
+			retried = append(retried, c.Now())
+		}
+		return errors.New("unavailable")
+	})
+
+	m.FireParallel(&tick{})
+	require.Eventually(t, func() bool {
+		c.Advance(time.Hour)
+		return calls.Load() == 3
+	}, time.Second, time.Millisecond)
+	m.Wait()
+	require.Len(t, retried, 2)
+	require.False(t, retried[0].Before(start.Add(time.Hour)))
+	require.False(t, retried[1].Before(retried[0].Add(2*time.Hour)))
+}
@@ -0,0 +1,19 @@
+package eventtest
+
+import (
+	"testing"
+
+	"github.com/robinbraemer/event"
+)
+
+// AssertNoLeaks asserts that mgr has no subscribers left and returns whether the assertion
+// was successful, e.g. registered with t.Cleanup to catch subscriptions never unsubscribed.
+// The subscriber of a Recorder is not counted.
+func AssertNoLeaks(t testing.TB, mgr event.Manager) bool {
+	t.Helper()
+	if n := mgr.SubscriberCount(); n != 0 {
+		t.Errorf("%d subscribers left, forgot to unsubscribe?", n)
+		return false
+	}
+	return true
+}
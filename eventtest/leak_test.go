@@ -0,0 +1,38 @@
+package eventtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/robinbraemer/event"
+)
+
+func TestAssertNoLeaks(t *testing.T) {
+	m := event.New()
+	require.True(t, AssertNoLeaks(t, m))
+
+	unsubscribe := event.Subscribe(m, 0, func(*myEvent) {})
+	unsubscribeTopic := m.SubscribeTopic("a.b", 0, func(any) {})
+	require.Equal(t, 2, m.SubscriberCount())
+	require.Equal(t, 1, m.SubscriberCount(&myEvent{}))
+	ft := &fakeTB{TB: t}
+	require.False(t, AssertNoLeaks(ft, m))
+	require.True(t, ft.failed)
+
+	unsubscribe()
+	unsubscribeTopic()
+	require.True(t, AssertNoLeaks(t, m))
+
+	m.SubscribeTopic("a.b", 0, func(any) {})
+	m.UnsubscribeAll()
+	require.True(t, AssertNoLeaks(t, m))
+
+	// The subscriber of a recorder is no leak
+	r := NewRecorder(m)
+	require.Zero(t, r.SubscriberCount(nil))
+	require.True(t, AssertNoLeaks(t, r))
+	event.SubscribeAll(r, 0, func(event.Event) {})
+	require.Equal(t, 1, r.SubscriberCount(nil, &myEvent{}))
+	require.False(t, AssertNoLeaks(ft, r))
+}
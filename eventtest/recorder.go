@@ -0,0 +1,154 @@
+// Package eventtest provides helpers for testing code using event managers.
+package eventtest
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/robinbraemer/event"
+)
+
+// Recorder is an event.Manager that records all events fired through the Manager it wraps.
+// It is safe for concurrent use, e.g. to record events fired by FireParallel.
+type Recorder struct {
+	event.Manager
+
+	mu        sync.Mutex    // Protects following fields
+	events    []event.Event // Recorded events in order of firing
+	recording bool          // Whether the subscriber of the Recorder is registered, false for event.Nop
+}
+
+// NewRecorder returns a new Recorder wrapping mgr.
+// It records events using a wildcard subscriber with the highest priority,
+// so events are recorded before any other subscriber can mutate them.
+func NewRecorder(mgr event.Manager) *Recorder {
+	r := &Recorder{Manager: mgr}
+	r.subscribe()
+	return r
+}
+
+func (r *Recorder) subscribe() {
+	before := r.Manager.SubscriberCount(any(nil))
+	r.Manager.Subscribe(any(nil), math.MaxInt, r.record)
+	recording := r.Manager.SubscriberCount(any(nil)) > before
+	r.mu.Lock()
+	r.recording = recording
+	r.mu.Unlock()
+}
+
+// isRecording reports whether the subscriber of the Recorder is registered.
+func (r *Recorder) isRecording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recording
+}
+
+// Unwrap returns the wrapped manager. Helpers of package event like Request, SubscribeCtx or
+// Unsubscribe use it to work on a Recorder like on the manager it wraps.
+func (r *Recorder) Unwrap() event.Manager {
+	return r.Manager
+}
+
+func (r *Recorder) record(e event.Event) {
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+}
+
+// UnsubscribeAll unsubscribes all subscribers of the given events, see event.Manager.
+// The subscriber of the Recorder is kept and not included in the returned count.
+func (r *Recorder) UnsubscribeAll(events ...event.Event) int {
+	return r.keepRecording(r.Manager.UnsubscribeAll, events)
+}
+
+// UnsubscribeAllAndWait is like UnsubscribeAll but waits for running handlers, see event.Manager.
+// The subscriber of the Recorder is kept and not included in the returned count.
+func (r *Recorder) UnsubscribeAllAndWait(events ...event.Event) (int, error) {
+	var err error
+	n := r.keepRecording(func(events ...event.Event) (n int) {
+		n, err = r.Manager.UnsubscribeAllAndWait(events...)
+		return n
+	}, events)
+	return n, err
+}
+
+// SubscriberCount returns the number of subscribers of the given events, see event.Manager.
+// The subscriber of the Recorder is not included.
+func (r *Recorder) SubscriberCount(events ...event.Event) int {
+	n := r.Manager.SubscriberCount(events...)
+	if !r.isRecording() {
+		return n
+	}
+	if len(events) == 0 {
+		return n - 1
+	}
+	for _, e := range events {
+		if e == nil {
+			n--
+		}
+	}
+	return n
+}
+
+// Reset resets the wrapped manager, see event.Manager.
+// The subscriber of the Recorder is kept and the recorded events are cleared.
+func (r *Recorder) Reset() {
+	r.Manager.Reset()
+	r.subscribe()
+	r.mu.Lock()
+	r.events = nil
+	r.mu.Unlock()
+}
+
+// keepRecording calls unsubscribeAll with the events and resubscribes the subscriber of
+// the Recorder if it was removed, i.e. for no events or the nil event.
+func (r *Recorder) keepRecording(unsubscribeAll func(...event.Event) int, events []event.Event) int {
+	removed := len(events) == 0
+	for _, e := range events {
+		removed = removed || e == nil
+	}
+	recording := r.isRecording()
+	n := unsubscribeAll(events...)
+	if removed {
+		r.subscribe()
+		if recording {
+			n--
+		}
+	}
+	return n
+}
+
+// Events returns all recorded events in order of firing.
+func (r *Recorder) Events() []event.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]event.Event(nil), r.events...)
+}
+
+// Fired returns the recorded events of exactly type t in order of firing.
+func (r *Recorder) Fired(t event.Type) []event.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var fired []event.Event
+	for _, e := range r.events {
+		if reflect.TypeOf(e) == t {
+			fired = append(fired, e)
+		}
+	}
+	return fired
+}
+
+// AssertFired asserts that an event deeply equal to e was recorded
+// and returns whether the assertion was successful.
+func (r *Recorder) AssertFired(t testing.TB, e event.Event) bool {
+	t.Helper()
+	for _, fired := range r.Fired(reflect.TypeOf(e)) {
+		if reflect.DeepEqual(fired, e) {
+			return true
+		}
+	}
+	t.Errorf("event %T %+v was not fired", e, e)
+	return false
+}
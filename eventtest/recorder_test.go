@@ -0,0 +1,86 @@
+package eventtest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/robinbraemer/event"
+)
+
+type myEvent struct{ s string }
+
+// fakeTB records failures instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeTB) Helper()               {}
+func (f *fakeTB) Errorf(string, ...any) { f.failed = true }
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(event.New())
+	event.Subscribe(r, 0, func(e *myEvent) { e.s += "!" })
+
+	r.Fire(&myEvent{s: "a"})
+	r.FireParallel(&myEvent{s: "b"})
+	r.Fire(myEvent{s: "c"})
+	r.Wait()
+
+	require.Len(t, r.Events(), 3)
+	require.Len(t, r.Fired(reflect.TypeOf(&myEvent{})), 2)
+	r.AssertFired(t, myEvent{s: "c"})
+	r.AssertFired(t, &myEvent{s: "a!"})
+
+	ft := &fakeTB{TB: t}
+	require.False(t, r.AssertFired(ft, &myEvent{s: "x"}))
+	require.True(t, ft.failed)
+
+	require.Equal(t, 1, r.UnsubscribeAll())
+	r.Fire(&myEvent{s: "d"})
+	r.AssertFired(t, &myEvent{s: "d"})
+
+	// Recording continues after a reset
+	r.Reset()
+	require.Empty(t, r.Events())
+	r.Fire(&myEvent{s: "e"})
+	require.Equal(t, []event.Event{&myEvent{s: "e"}}, r.Events())
+	require.Zero(t, r.SubscriberCount())
+}
+
+func TestRecorderUnsubscribeAllWildcard(t *testing.T) {
+	r := NewRecorder(event.New())
+	r.Subscribe(any(nil), 0, func(event.Event) {})
+	require.Equal(t, 1, r.SubscriberCount(nil))
+
+	require.Equal(t, 1, r.UnsubscribeAll(nil))
+	r.Fire(&myEvent{s: "a"})
+	r.AssertFired(t, &myEvent{s: "a"})
+	require.Zero(t, r.SubscriberCount(nil))
+}
+
+func TestRecorderNop(t *testing.T) {
+	r := NewRecorder(event.Nop)
+	require.Zero(t, r.SubscriberCount())
+	require.Zero(t, r.SubscriberCount(nil))
+	require.Zero(t, r.UnsubscribeAll())
+}
+
+func TestRecorderUnwrap(t *testing.T) {
+	r := NewRecorder(event.New())
+	event.SubscribeCtx(r, 0, func(ctx context.Context, e *myEvent) {
+		event.Reply(ctx, &myEvent{s: e.s + "!"})
+	})
+	resp, err := event.Request[*myEvent, *myEvent](r, &myEvent{s: "ping"}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "ping!", resp.s)
+	r.AssertFired(t, &myEvent{s: "ping"})
+
+	handler := func(*myEvent) {}
+	event.Subscribe(r, 0, handler)
+	require.True(t, event.Unsubscribe(r, handler))
+}
@@ -1,7 +1,12 @@
 package event
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 )
@@ -9,48 +14,443 @@ import (
 // Manager is an event manager to subscribe, fire events and
 // decouple the event source and sink in a complex system.
 type Manager interface {
-	// Subscribe subscribes a handler to an event type with a priority
-	// and returns a func that can be run to unsubscribe the handler.
+	Publisher
+	Subscriber
+
+	// Wait blocks until no event handlers are running for the specified events.
+	// If no events are specified it waits for all events.
 	//
-	// HandlerFunc should return as soon as possible and start long-running tasks in parallel.
-	// The Type can be any type, pointer to type or reflect.Type and the handler is only run for
-	// the exact type subscribed for.
+	// Waiting for specific events blocks until no fire of their types is running, regardless of
+	// changes to the subscribers like UnsubscribeAll. This covers all subscribers called by those
+	// fires, including wildcard subscribers, and the after handlers of FireParallel calls.
+	// Fires started while waiting are waited for as well until the type is idle once.
+	// Waiting for the nil event also waits for all wildcard subscribers.
+	Wait(events ...Event)
+	// Drain is like Wait for all events but returns early if ctx is done, e.g. for graceful shutdown.
+	// The returned error then wraps the context error and reports the number of fires still in flight.
+	Drain(ctx context.Context) error
+	// ParallelBatch returns a new ParallelBatch to fire events like FireParallel
+	// and wait for only those, e.g. to await a fan-out without the coarse Wait.
+	ParallelBatch() *ParallelBatch
+	// Close dispatches events pending by WithCoalesce, waits for all running event handlers like Wait
+	// and then runs the shutdown hooks added by WithShutdownHook in reverse order.
+	// Subsequent calls do nothing and return nil.
+	// Fires after Close are handled as set by WithPostCloseBehavior, dropping them by default.
+	Close() error
+	// InFlight returns the number of Fire and FireParallel calls currently in flight.
+	// It is a cheap gauge meant for observation only, e.g. for health checks.
+	InFlight() int
+	// QueueDepth returns the number of fires waiting to be dispatched, which are the tasks submitted
+	// to the scheduler of WithScheduler not yet started, the fires blocked by WithInflightLimit, the
+	// fires buffered for types paused by Pause and the pending fires of WithCoalesce. It returns 0
+	// without those options. Like InFlight it is a cheap gauge, e.g. for autoscaling.
+	QueueDepth() int
+	// HasSubscriber determines whether all given events have at least one subscriber.
+	// If no events are specified it returns true if there are any subscribers for any event.
 	//
-	// HandlerFunc always gets the fired event of the same subscribed eventType or the same type as
-	// represented by reflect.Type.
-	Subscribe(eventType Event, priority int, fn HandlerFunc) (unsubscribe func())
+	// It is useful to check whether an event is subscribed for before firing it when
+	// the event value is expensive to create.
+	HasSubscriber(events ...Event) bool
+	// HasSubscriberType is like HasSubscriber for a single event type, e.g. from KeyOf, but avoids
+	// the variadic slice and the type key func for hot guard checks. The nil type checks wildcard
+	// subscribers only.
+	HasSubscriberType(t Type) bool
+	// Stats returns the number of fired events of the event's type and when the last one was fired.
+	// It returns zero values for types that were never fired.
+	Stats(event Event) (fired int64, lastFired time.Time)
+	// SubscriberCount returns the number of subscribers of the types of the events, counting wildcard
+	// subscribers for the nil event only. If no events are specified it returns the number of all
+	// subscribers including topic subscribers, e.g. to detect subscriber leaks in tests.
+	SubscriberCount(events ...Event) int
+	// RegistrationStats returns the number of subscribers added and removed over the lifetime of the
+	// manager, including topic subscribers and those removed by UnsubscribeAll and Reset.
+	// A steadily growing gap between both hints at leaked subscriptions, e.g. in long-running services.
+	RegistrationStats() (subscribed, unsubscribed int64)
+	// StuckHandlers returns the subscriber calls currently running for longer than olderThan,
+	// e.g. to detect deadlocked handlers in health checks. It always returns none
+	// unless handler tracking is enabled by WithHandlerTracking.
+	StuckHandlers(olderThan time.Duration) []HandlerInfo
+
+	// PanicChan returns a channel receiving every panic recovered from a subscriber, e.g. to route
+	// panics to alerting independent of the logger. Panics are sent without blocking, so they are
+	// dropped while the buffer of 64 panics is full or no one is receiving. Panics are only sent
+	// after the first call. The channel is closed by Close after all running handlers are done.
+	PanicChan() <-chan PanicError
+
+	// Pause stops dispatching events of the types of the events, e.g. for maintenance windows.
+	// Fires of paused types return immediately and buffer the event to be dispatched on Resume,
+	// up to the limit set by WithPauseBuffer per type. Further fires are dropped and counted.
+	// Pausing an already paused type does nothing.
+	//
+	// Buffered events are retained in memory until resumed and are not covered by Wait.
+	// The after handlers of FireParallel run without waiting for buffered events.
+	Pause(events ...Event)
+	// Resume dispatches the buffered events of the paused types of the events in order of firing
+	// in the calling goroutine and resumes dispatching them. Events fired meanwhile are dispatched
+	// after the buffered ones. It returns the number of events dropped since pausing.
+	// If a subscriber panics, the remaining events stay buffered until Resume is called again.
+	Resume(events ...Event) (dropped int)
+	// Refire dispatches the most recent event retained by WithReplay of each type of the events
+	// to the current subscribers like Fire, e.g. to propagate configuration after hot-reloading.
+	// Types without a retained event are skipped, and refired events are not retained again.
+	// It returns the number of events dispatched.
+	Refire(events ...Event) int
+
+	// Reset returns the manager to the state right after construction by removing all subscribers,
+	// stats and retained events and resuming paused types without dispatching their buffered events,
+	// while keeping the options and registered type names.
+	// Handlers still running are not waited for and complete with the state they started with.
+	Reset()
+
+	// RegisterType registers a name for the type of the sample event.
+	// Registered names are used in logs instead of the reflect.Type string
+	// and can be resolved back to the type with TypeByName.
+	// Registering an already registered name replaces its type.
+	RegisterType(name string, sample Event)
+	// TypeByName returns the type registered with RegisterType for the name.
+	TypeByName(name string) (Type, bool)
+}
 
+// Publisher is the part of a Manager to fire events, holding the Fire methods only.
+// Code that only fires events, e.g. a handler re-firing events, should depend on it only.
+// Lifecycle and introspection methods like Wait or HasSubscriber are part of Manager.
+type Publisher interface {
 	// Fire fires an event in the calling goroutine and returns after all subscribers are complete handling it.
 	// Any panic by a subscriber is caught so firing the event to the next subscriber can proceed.
+	//
+	// Firing an untyped nil event only calls wildcard subscribers. A typed nil pointer like (*T)(nil)
+	// is an event of type *T and calls the subscribers of *T with the nil pointer, while
+	// unified and embedded matching skip it. The same applies to all other fire methods.
 	Fire(Event)
+	// FireTagged fires an event like Fire but only to the subscribers whose tags the filter returns
+	// true for, e.g. for tenant-scoped handlers over a shared bus. The filter is called with the tags
+	// of SubscribeTagged and with no tags for all other subscribers, including wildcard subscribers.
+	FireTagged(event Event, tagFilter func(tags []string) bool)
+	// FireTopic calls the subscribers of all topic patterns matching the topic with the payload
+	// in order of priority. For equal priorities, subscribers of exact patterns are called before
+	// subscribers of patterns with wildcards, and otherwise in order of subscription.
+	// The topic itself should not contain wildcard segments.
+	FireTopic(topic string, payload any)
+	// FireTo fires an event like Fire but only to the subscribers for which match returns true,
+	// in order of priority, e.g. to re-deliver an event to a single handler when troubleshooting.
+	// The Subscription passed to match is the handle of the subscriber, including wildcard subscribers.
+	FireTo(event Event, match func(Subscription) bool)
+	// FireNoRecover fires an event like Fire but lets a subscriber panic propagate to the caller
+	// with its full stack even if panic recovery is enabled, e.g. to pinpoint bugs in tests.
+	// Subscribers after the panicking one are not called. The manager state stays consistent,
+	// so Wait does not block on the aborted fire.
+	FireNoRecover(event Event)
+	// FireErr fires an event like Fire but always recovers subscriber panics, even if panic recovery
+	// is disabled, and returns the first one as *PanicError. Subscribers after a panicking one are still called.
+	// After Close it returns ErrClosed with PostCloseError, see WithPostCloseBehavior.
+	FireErr(event Event) error
+	// FireNoWildcard fires an event like Fire but skips wildcard subscribers, e.g. for internal
+	// bookkeeping events. Wildcard subscribers like audit loggers do not see the event at all,
+	// only the hooks of WithBeforeFire and WithAfterFire still run.
+	FireNoWildcard(event Event)
+	// FireMeta fires an event like Fire and passes the metadata to the subscribers of SubscribeMeta,
+	// e.g. for trace ids without adding them to every event type. The map is copied before the first
+	// subscriber is called, so the caller may modify it afterwards and subscribers share a read-only view.
+	FireMeta(event Event, meta map[string]any)
+	// FireCtx fires an event like Fire with a context.
+	// The observers added to ctx by WithContextObserver are called after all subscribers.
+	// Subscribers are skipped once the deadline of ctx passed if enabled by WithDeadlineEnforcement.
+	FireCtx(ctx context.Context, event Event)
 	// FireParallel fires an event in a new goroutine and returns immediately.
 	// The subscribers are called in order of priority and the event value is passed to the next subscriber.
 	//
 	// It optionally runs handlers in the goroutine after all subscribers are done.
 	// If an after handler panics no further handlers in the slice are run.
 	FireParallel(event Event, after ...HandlerFunc)
+	// FireParallelHandle is like FireParallel but returns a handle to wait for this fire only.
+	// The handle is done once all subscribers and after handlers are done.
+	FireParallelHandle(event Event, after ...HandlerFunc) *FireHandle
+	// FireParallelConcurrent is like FireParallel but calls all subscribers concurrently
+	// like FireConcurrent. The after handlers are run once all subscribers are done.
+	FireParallelConcurrent(event Event, after ...HandlerFunc)
+	// FireRange fires an event like Fire but only calls the subscribers with a priority
+	// in the range [minPriority, maxPriority], e.g. to run multi-phase pipelines.
+	// The range applies to wildcard subscribers as well.
+	FireRange(event Event, minPriority, maxPriority int)
+	// FireConcurrent fires an event in the calling goroutine but calls all subscribers
+	// concurrently in new goroutines and returns after all of them are complete.
+	// Priorities are ignored, so it trades ordering for latency when subscribers are independent.
+	// Any panic by a subscriber is caught individually.
+	FireConcurrent(event Event)
+	// FireLazy fires the event returned by build in the calling goroutine, but only calls
+	// build if eventType has at least one subscriber at the time of the call.
+	// It is useful when the event value is expensive to create and replaces
+	// the racy HasSubscriber and Fire combination.
+	//
+	// The event returned by build must be of eventType.
+	FireLazy(eventType Event, build func() Event)
+	// FireJSON decodes data into a new event of the type registered for name
+	// and fires it in the calling goroutine like Fire.
+	// It returns an error wrapping ErrUnknownType if the name is not registered
+	// or the error of decoding data without firing.
+	FireJSON(name string, data []byte) error
+}
 
-	// Wait blocks until no event handlers are running for the specified events.
-	// If no events are specified it waits for all events.
-	Wait(events ...Event)
-
-	// HasSubscriber determines whether all given events have at least one subscriber.
-	// If no events are specified it returns true if there are any subscribers for any event.
+// Subscriber is the part of a Manager to subscribe to events, holding the subscribe and
+// unsubscribe methods only. Code that only subscribes handlers should depend on it only.
+type Subscriber interface {
+	// Subscribe subscribes a handler to an event type with a priority
+	// and returns a func that can be run to unsubscribe the handler.
 	//
-	// It is useful to check whether an event is subscribed for before firing it when
-	// the event value is expensive to create.
-	HasSubscriber(events ...Event) bool
+	// HandlerFunc should return as soon as possible and start long-running tasks in parallel.
+	// The Type can be any type, pointer to type or reflect.Type and the handler is only run for
+	// the exact type subscribed for.
+	//
+	// HandlerFunc always gets the fired event of the same subscribed eventType or the same type as
+	// represented by reflect.Type.
+	//
+	// Handlers may subscribe and unsubscribe, including themselves, while being called. Fires never hold
+	// a lock while calling subscribers and iterate a snapshot of them, so changes take effect on the
+	// next fire: a subscriber added by a handler is not called by the current fire, and a subscriber
+	// removed by a handler is still called by the current fire if it comes later.
+	Subscribe(eventType Event, priority int, fn HandlerFunc) (unsubscribe func())
+	// SubscribeTopic subscribes a handler to a dot-separated topic pattern like "user.created" with
+	// a priority, as an alternative to routing by Go types. A "*" segment matches exactly one segment
+	// and a trailing "**" segment matches one or more segments, e.g. "user.*" matches "user.created".
+	// Topic subscribers are separate from event type subscribers, including wildcard subscribers.
+	SubscribeTopic(pattern string, priority int, fn func(payload any)) (unsubscribe func())
 	// UnsubscribeAll unsubscribes all subscribers of the given events
 	// and returns the number of subscribers unsubscribed.
+	// If no events are specified it unsubscribes all subscribers including topic subscribers.
 	UnsubscribeAll(events ...Event) int
+	// UnsubscribeAllAndWait is like UnsubscribeAll but additionally blocks until
+	// handlers of the unsubscribed events that are still running have returned.
+	// The returned error joins the panics recovered from the unsubscribed handlers while waiting
+	// as *PanicError, e.g. for teardown code to report misbehaving handlers.
+	//
+	// It must not be called from within a handler of one of the given events, or of any event if
+	// none are given, since waiting for the calling handler itself would never return. A handler
+	// may call it in a new goroutine instead, which returns once the handler has returned.
+	UnsubscribeAllAndWait(events ...Event) (int, error)
 }
 
 // Subscribe subscribes a handler to an event type with a priority.
 // The event type is inferred from the argument of the handler.
 // See Manager.Subscribe for more details.
-func Subscribe[T Event](mgr Manager, priority int, handler func(T)) (unsubscribe func()) {
+//
+// Events not of type T are skipped instead of panicking, e.g. when the handler is reached by
+// events of other types sharing a key of WithTypeKeyFunc.
+func Subscribe[T Event](mgr Subscriber, priority int, handler func(T)) (unsubscribe func()) {
+	var typ T
+	return subscribe(mgr, typ, &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev)
+			}
+		},
+		handler: handler,
+	})
+}
+
+// SubscribeTagged is like Subscribe but attaches tags to the subscriber matched by Publisher.FireTagged.
+// Other fire methods call tagged subscribers like any other. Managers not created by New ignore the tags.
+func SubscribeTagged[T Event](mgr Subscriber, priority int, tags []string, handler func(T)) (unsubscribe func()) {
+	var typ T
+	return subscribe(mgr, typ, &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev)
+			}
+		},
+		handler: handler,
+		tags:    append([]string(nil), tags...),
+	})
+}
+
+// SubscribeRetry is like Subscribe for a handler returning an error, e.g. calling an unreliable service.
+// A failed call is retried up to retries times, waiting backoff by the Clock of the manager before
+// the first retry and doubling the wait for each further retry. The last error is logged once all
+// retries failed.
+// Managers not created by New do not log.
+//
+// Retries happen within the subscriber's call, so they delay all subsequent subscribers
+// of the fire unless firing concurrently, e.g. with FireConcurrent.
+func SubscribeRetry[T Event](mgr Subscriber, priority int, retries int, backoff time.Duration, handler func(T) error) (unsubscribe func()) {
+	var typ T
+	clock := clockOf(mgr)
+	sub := &subscriber{priority: priority, handler: handler}
+	sub.fn = func(e Event) {
+		ev, ok := e.(T)
+		if !ok {
+			return
+		}
+		err := handler(ev)
+		for i, wait := 0, backoff; err != nil && i < retries; i, wait = i+1, wait*2 {
+			waited := make(chan struct{})
+			clock.AfterFunc(wait, func() { close(waited) })
+			<-waited
+			err = handler(ev)
+		}
+		if m, ok := asManager(mgr); ok && err != nil {
+			m.log.WithValues(sub.logValues()...).Error(err, "event subscriber failed after retries",
+				"eventType", m.typeName(m.typeOf(e)),
+				"retries", retries)
+		}
+	}
+	return subscribe(mgr, typ, sub)
+}
+
+// SubscribeTTL is like Subscribe but unsubscribes the handler once ttl elapsed by the Clock
+// of the manager, e.g. for temporary watchers. The returned func unsubscribes early and stops
+// the timer. Fires running at expiry may still call the handler. See Subscribe for more details.
+func SubscribeTTL[T Event](mgr Subscriber, priority int, ttl time.Duration, handler func(T)) (unsubscribe func()) {
+	unsub := Subscribe(mgr, priority, handler)
+	timer := clockOf(mgr).AfterFunc(ttl, unsub)
+	return func() {
+		timer.Stop()
+		unsub()
+	}
+}
+
+// KeyOf returns the event type of T without an instance, e.g. for options taking a Type
+// or maps keyed by event type. It is nil for interface types, which Subscribe subscribes
+// as wildcard. Keys of WithTypeKeyFunc are not applied.
+func KeyOf[T Event]() Type {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	return t
+}
+
+// HasSubscriber reports whether an event of type T would be fired to any subscriber,
+// including wildcard subscribers, without constructing an event, e.g. HasSubscriber[*UserCreated](mgr).
+// For interface types only wildcard subscribers are considered. See Manager.HasSubscriberType.
+//
+// Like Subscribe, the key of WithTypeKeyFunc is resolved from the zero value of T.
+func HasSubscriber[T Event](mgr Manager) bool {
+	t := KeyOf[T]()
+	if m, ok := asManager(mgr); ok && m.typeKey != nil && t != nil {
+		var typ T
+		t = m.typeOf(typ)
+	}
+	return mgr.HasSubscriberType(t)
+}
+
+// SubscribeNamed is like Subscribe but attaches a name to the subscriber that is used in
+// logs and HandlerInfo to identify it, e.g. in panic logs instead of its priority only.
+// Names need not be unique. Managers not created by New ignore the name.
+func SubscribeNamed[T Event](mgr Subscriber, name string, priority int, handler func(T)) (unsubscribe func()) {
+	var typ T
+	return subscribe(mgr, typ, &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev)
+			}
+		},
+		handler: handler,
+		name:    name,
+	})
+}
+
+// SubscribeCleanup subscribes a handler to the event type T that must run after all other subscribers,
+// e.g. to clean up or commit. Cleanup subscribers sort after subscribers of every int priority,
+// including math.MinInt, and run in order of subscription among themselves.
+// Managers not created by New subscribe the handler with PriorityCleanup.
+// See Subscribe for more details.
+func SubscribeCleanup[T Event](mgr Subscriber, handler func(T)) (unsubscribe func()) {
 	var typ T
-	return mgr.Subscribe(typ, priority, func(e Event) { handler(e.(T)) })
+	return subscribe(mgr, typ, &subscriber{
+		priority: PriorityCleanup,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev)
+			}
+		},
+		handler: handler,
+		cleanup: true,
+	})
+}
+
+// Unsubscribe unsubscribes the first subscriber of the event type T that was subscribed
+// by Subscribe with the same handler func and returns whether a subscriber was removed.
+// It is an alternative to keeping the unsubscribe func returned by Subscribe.
+//
+// Handlers are compared by their code pointer, so distinct closures created by the same
+// func literal are considered equal even if they capture different variables.
+// Managers not created by New are not supported and false is returned.
+func Unsubscribe[T Event](mgr Subscriber, handler func(T)) bool {
+	m, ok := asManager(mgr)
+	if !ok {
+		return false
+	}
+	var typ T
+	return m.removeFunc(m.typeOf(typ), handler)
+}
+
+// SubscribeAll subscribes a wildcard handler that receives every fired event regardless of its type.
+// It is equal to subscribing to the type any(nil). See Manager.Subscribe for more details.
+func SubscribeAll(mgr Subscriber, priority int, fn func(Event)) (unsubscribe func()) {
+	return mgr.Subscribe(any(nil), priority, fn)
+}
+
+// SubscribeValue subscribes a handler receiving the fired event as reflect.Value.
+// See Manager.Subscribe for more details.
+//
+// It is meant for reflection-based tooling that would otherwise call reflect.ValueOf
+// in the handler. Handlers that know the event type should prefer Subscribe, since a
+// typed assertion is cheaper than creating a reflect.Value for every fired event.
+func SubscribeValue(mgr Subscriber, eventType Event, priority int, fn func(reflect.Value)) (unsubscribe func()) {
+	return subscribe(mgr, eventType, &subscriber{
+		priority: priority,
+		fn:       func(e Event) { fn(reflect.ValueOf(e)) },
+		handler:  fn,
+	})
+}
+
+// Merge returns a func running all unsubscribe funcs in order, e.g. to clean up all
+// subscriptions of a component at once. A panicking func does not prevent the others
+// from running, and the first panic is re-panicked after all funcs ran.
+func Merge(unsubs ...func()) (unsubscribe func()) {
+	return func() {
+		var (
+			panicked bool
+			first    any
+		)
+		for _, unsub := range unsubs {
+			func() {
+				defer func() {
+					if r := recover(); r != nil && !panicked {
+						panicked, first = true, r
+					}
+				}()
+				unsub()
+			}()
+		}
+		if panicked {
+			panic(first)
+		}
+	}
+}
+
+// FireReduce fires an event like Fire and folds the contributions of its subscribers into a result,
+// e.g. to build a response from subscribers writing into the event. Starting with initial, combine is
+// called with the result so far and the event after each subscriber, in the order subscribers are called.
+// A panicking subscriber still counts as called. Subscribers receiving a value of another type,
+// like unified or embedded subscribers, are skipped by combine.
+//
+// Managers not created by New call combine only once after all subscribers.
+func FireReduce[T Event, R any](mgr Publisher, event T, initial R, combine func(R, T) R) R {
+	acc := initial
+	m, ok := asManager(mgr)
+	if !ok {
+		mgr.Fire(event)
+		return combine(acc, event)
+	}
+	m.fireEach(event, func(e Event) {
+		if ev, ok := e.(T); ok {
+			acc = combine(acc, ev)
+		}
+	})
+	return acc
 }
 
 // FireParallel fires an event in a new goroutine and returns immediately.
@@ -58,26 +458,219 @@ func Subscribe[T Event](mgr Manager, priority int, handler func(T)) (unsubscribe
 //
 // It optionally runs handlers in the goroutine after all subscribers are done.
 // If an after handler panics no further handlers in the slice are run.
-func FireParallel[T Event](mgr Manager, event T, after ...func(T)) {
+//
+// The after handlers receive the event dispatched to the subscribers, so mutations by subscribers
+// through a pointer event are visible to them, while value events are copies subscribers cannot change.
+// An untyped nil event is passed as the zero value of T.
+func FireParallel[T Event](mgr Publisher, event T, after ...func(T)) {
 	mgr.FireParallel(event, func(e Event) {
-		ev := e.(T)
+		ev, _ := e.(T)
 		for _, fn := range after {
 			fn(ev)
 		}
 	})
 }
 
+// FireLazy fires the event returned by build, but only calls build if
+// the event type T has at least one subscriber.
+// See Manager.FireLazy for more details.
+func FireLazy[T Event](mgr Publisher, build func() T) {
+	var typ T
+	mgr.FireLazy(typ, func() Event { return build() })
+}
+
 // FireParallelChan fires an event in a new goroutine and returns a result channel immediately.
 // The subscribers are called in order of priority and the event value is passed to the next subscriber.
-func FireParallelChan[T Event](mgr Manager, event T) (resultChan <-chan T) {
+// The channel receives the event dispatched to the subscribers like the after handlers of FireParallel.
+func FireParallelChan[T Event](mgr Publisher, event T) (resultChan <-chan T) {
 	result := make(chan T, 1)
 	FireParallel(mgr, event, func(e T) {
-		result <- event
+		result <- e
 		close(result)
 	})
 	return result
 }
 
+// FireParallelChanCtx is like FireParallelChan but stops calling further subscribers once ctx is done,
+// in which case the channel is closed without a value, so callers giving up waiting leak no work.
+// Otherwise the channel receives the event dispatched to the subscribers like for FireParallelChan.
+// The observers added to ctx by WithContextObserver are called like for FireCtx.
+// Managers not created by New call all subscribers but still close the channel without a value.
+func FireParallelChanCtx[T Event](ctx context.Context, mgr Publisher, event T) (resultChan <-chan T) {
+	result := make(chan T, 1)
+	after := func(e Event) {
+		if ctx.Err() == nil {
+			ev, _ := e.(T)
+			result <- ev
+		}
+		close(result)
+	}
+	if m, ok := asManager(mgr); ok {
+		m.fireParallel(event, &fireOptions{
+			ctx:    ctx,
+			filter: func(*subscriber) bool { return ctx.Err() == nil },
+		}, []HandlerFunc{after}, nil)
+	} else {
+		mgr.FireParallel(event, after)
+	}
+	return result
+}
+
+// FireParallelErr fires an event in a new goroutine like FireParallel and returns a channel
+// receiving the first error returned by the after funcs or a panic recovered from them as *PanicError.
+// No further after funcs are run after an error. The channel is buffered
+// and closed after at most one error was sent, so it is closed without a value on success.
+func FireParallelErr[T Event](mgr Publisher, event T, after ...func(T) error) <-chan error {
+	errs := make(chan error, 1)
+	mgr.FireParallel(event, func(e Event) {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r, typeOf(e), 0)
+			}
+			if err != nil {
+				errs <- err
+			}
+			close(errs)
+		}()
+		ev, _ := e.(T)
+		for _, fn := range after {
+			if err = fn(ev); err != nil {
+				return
+			}
+		}
+	})
+	return errs
+}
+
+// FireParallelSync fires an event in a new goroutine like FireParallel
+// but blocks until all subscribers are done and returns the event.
+func FireParallelSync[T Event](mgr Publisher, event T) T {
+	return <-FireParallelChan(mgr, event)
+}
+
+// BridgeChan fires every value received from ch in a new goroutine until ctx is done or ch is closed
+// and returns a channel that is closed when the goroutine has returned.
+// Values are fired one after another like Fire, so Wait covers the value currently being fired.
+func BridgeChan[T Event](ctx context.Context, mgr Publisher, ch <-chan T) (done <-chan struct{}) {
+	d := make(chan struct{})
+	go func() {
+		defer close(d)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-ch:
+				if !ok {
+					return
+				}
+				mgr.Fire(e)
+			}
+		}
+	}()
+	return d
+}
+
+// FireBatch fires the events in order in the calling goroutine
+// and returns after all subscribers are complete handling them.
+func FireBatch(mgr Publisher, events ...Event) {
+	for _, e := range events {
+		mgr.Fire(e)
+	}
+}
+
+// FireAll fires the events of type T in order in the calling goroutine like FireBatch,
+// but resolves the subscribers of T once for all events, e.g. to dispatch many homogeneous events.
+// Subscribers of T added while firing may not be called for the remaining events.
+// For interface types or with WithTypeKeyFunc each event is resolved separately.
+func FireAll[T Event](mgr Publisher, events []T) {
+	m, ok := asManager(mgr)
+	t := KeyOf[T]()
+	if !ok || t == nil || m.typeKey != nil {
+		for _, e := range events {
+			mgr.Fire(e)
+		}
+		return
+	}
+	m.enter()
+	defer m.exit()
+	list, anyList := m.lists(t)
+	for _, e := range events {
+		m.dispatch(e, t, list, anyList, nil)
+	}
+}
+
+// FireBatchUnique is like FireBatch but fires pointer events appearing
+// multiple times in the batch only once, preserving the order of first occurrences.
+// Non-pointer events are never deduplicated.
+func FireBatchUnique(mgr Publisher, events ...Event) {
+	FireBatchUniqueKey(mgr, func(e Event) any {
+		if reflect.ValueOf(e).Kind() == reflect.Pointer {
+			return e
+		}
+		return nil
+	}, events...)
+}
+
+// FireBatchUniqueKey is like FireBatch but fires events with the same key only once,
+// preserving the order of first occurrences. The key func must return comparable values.
+// Events with a nil key are never deduplicated.
+func FireBatchUniqueKey(mgr Publisher, key func(Event) any, events ...Event) {
+	seen := make(map[any]struct{}, len(events))
+	for _, e := range events {
+		if k := key(e); k != nil {
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+		}
+		mgr.Fire(e)
+	}
+}
+
+// SubscriberChanged is fired by a Manager with lifecycle events enabled after a subscriber
+// of an event type was added or removed. It is never fired for subscribers of itself.
+type SubscriberChanged struct {
+	Type  Type // The type subscribed to, nil for wildcard subscribers
+	Count int  // The new number of subscribers of the type
+}
+
+// FireHandle is a handle to a single fire returned by Publisher.FireParallelHandle.
+type FireHandle struct {
+	done chan struct{}
+}
+
+// Done returns a channel that is closed when the fire is done.
+func (h *FireHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until the fire is done or ctx is done and returns the context error in the latter case.
+func (h *FireHandle) Wait(ctx context.Context) error {
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandlerInfo describes a running subscriber call reported by Manager.StuckHandlers.
+type HandlerInfo struct {
+	Type     Type      // The event type the subscriber is called for
+	Priority int       // The priority of the subscriber
+	Name     string    // The name of the subscriber if subscribed by SubscribeNamed
+	Started  time.Time // When the call started
+}
+
+// ErrUnknownType is returned when an event type name is not registered.
+var ErrUnknownType = errors.New("unknown event type")
+
+// PriorityCleanup is the priority reported for subscribers of SubscribeCleanup, which run after all
+// other subscribers. It is the lowest possible priority, but passing it to Subscribe subscribes a normal
+// subscriber that runs before all subscribers of SubscribeCleanup.
+const PriorityCleanup = math.MinInt
+
 // HandlerFunc is an event handler.
 type HandlerFunc func(e Event)
 
@@ -90,13 +683,24 @@ type Type reflect.Type
 // New returns a new event Manager.
 func New(opts ...ManagerOption) Manager {
 	m := &manager{
-		subscribers:  make(map[Type]*subscriberList),
+		typeNames:    make(map[Type]string),
+		namedTypes:   make(map[string]Type),
 		recoverPanic: true,
 		log:          logr.Discard(),
+		pauseBuffer:  defaultPauseBuffer,
+		clock:        realClock{},
+		envelopeID:   newUUID,
+		panics:       make(chan PanicError, panicChanBuffer),
+		paused:       make(map[Type]*pauseBuffer),
 	}
 	for _, opt := range opts {
 		opt(m)
 	}
+	m.states = make(map[Type]*typeState, m.expectedTypes)
+	m.running = make(map[*HandlerInfo]struct{})
+	if len(m.logValues) != 0 {
+		m.log = m.log.WithValues(m.logValues...)
+	}
 	return m
 }
 
@@ -118,3 +722,211 @@ func WithLogger(log logr.Logger) ManagerOption {
 		m.log = log
 	}
 }
+
+// WithLogValues returns a ManagerOption that adds key-value pairs to all logs of the manager,
+// e.g. a service name or instance id, regardless of the order of WithLogger.
+func WithLogValues(keysAndValues ...any) ManagerOption {
+	return func(m *manager) {
+		m.logValues = append(m.logValues, keysAndValues...)
+	}
+}
+
+// WithBeforeFire returns a ManagerOption that sets a hook run once per fired event
+// before any subscriber is called, even if the event has no subscribers.
+// The hook is panic-recovered like subscribers and does not affect propagation.
+func WithBeforeFire(fn func(Type, Event)) ManagerOption {
+	return func(m *manager) {
+		m.beforeFire = fn
+	}
+}
+
+// WithAfterFire returns a ManagerOption that sets a hook run once per fired event
+// after all subscribers, including wildcard subscribers, are done handling it.
+// The hook is panic-recovered like subscribers and does not affect propagation.
+func WithAfterFire(fn func(Type, Event)) ManagerOption {
+	return func(m *manager) {
+		m.afterFire = fn
+	}
+}
+
+// WithDuplicateDetection returns a ManagerOption that enables/disables logging a warning
+// when a handler func is subscribed to an event type it is already subscribed to.
+// The subscription is not prevented. Default is false.
+//
+// Handlers are compared by their code pointer, so distinct closures created by the
+// same func literal are reported as duplicates even if they capture different variables.
+func WithDuplicateDetection(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.detectDuplicates = enabled
+	}
+}
+
+// WithSlowHandlerThreshold returns a ManagerOption that calls onSlow with the event type,
+// subscriber priority and duration whenever a subscriber takes longer than d to handle an event.
+// Panicking subscribers are measured up to the panic. The fire is not affected.
+//
+// Use WithSlowHandlerInfo to also receive the name of the subscriber.
+func WithSlowHandlerThreshold(d time.Duration, onSlow func(Type, int, time.Duration)) ManagerOption {
+	return WithSlowHandlerInfo(d, func(info HandlerInfo, d time.Duration) {
+		onSlow(info.Type, info.Priority, d)
+	})
+}
+
+// WithSlowHandlerInfo is like WithSlowHandlerThreshold but calls onSlow with the HandlerInfo of
+// the slow call, including the subscriber name if subscribed by SubscribeNamed.
+func WithSlowHandlerInfo(d time.Duration, onSlow func(HandlerInfo, time.Duration)) ManagerOption {
+	return func(m *manager) {
+		m.slowThreshold = d
+		m.onSlow = onSlow
+	}
+}
+
+// WithPtrValueUnification returns a ManagerOption that enables/disables firing events of
+// a pointer type *T also to subscribers of T, and events of T also to subscribers of *T.
+// Default is false, subscribers are only called for events of the exact subscribed type.
+//
+// Subscribers of T receive a dereferenced copy of a fired *T, so their mutations are not
+// visible to the firing caller. Subscribers of *T receive the address of a copy of a fired T.
+// Unified subscribers are called after the subscribers of the exact type.
+func WithPtrValueUnification(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.unifyPtrValue = enabled
+	}
+}
+
+// WithEmbeddedMatching returns a ManagerOption that enables/disables firing the exported embedded
+// struct fields of struct events also to the subscribers of the embedded types. For example
+// subscribers of BaseEvent receive the BaseEvent field of a fired UserCreated embedding it.
+// Default is false.
+//
+// Subscribers of an embedded value type receive a copy of the field. For pointer events, subscribers
+// of the pointer type receive the address of the field, so their mutations are visible to the caller.
+// Embedded pointer fields are passed as is and skipped if nil.
+// Embedded fields are walked up to a depth of 3 and always keyed by their Go type,
+// ignoring the func of WithTypeKeyFunc. Embedded subscribers are called after all other subscribers.
+//
+// Enabling it costs a reflection walk of the event's struct type on every fire.
+func WithEmbeddedMatching(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.matchEmbedded = enabled
+	}
+}
+
+// WithTypeKeyFunc returns a ManagerOption that sets a func returning the key subscribers of an event
+// are looked up by, instead of its reflect.Type. This allows modelling the event type by a field,
+// e.g. events of different Go types with an equal name field can share subscribers.
+// If the func returns nil the reflect.Type of the event is used.
+// Names registered with RegisterType are used for the keys in logs.
+//
+// The func is also applied to the eventType argument of Subscribe and the other methods accepting
+// an event, so they must be passed a sample event with the key of interest. A reflect.Type or
+// reflect.Value argument is used as the key itself. Generic helpers like Subscribe pass the zero value
+// of their type parameter. Pointer and value unification is based on Go types, not on keys.
+//
+// Handlers may receive events of different Go types sharing a key and must not assume the type.
+func WithTypeKeyFunc(fn func(Event) Type) ManagerOption {
+	return func(m *manager) {
+		m.typeKey = fn
+	}
+}
+
+// WithLifecycleEvents returns a ManagerOption that enables/disables firing a *SubscriberChanged
+// event whenever a subscriber is added or removed, e.g. for live dashboards of the subscriber topology.
+// Default is false.
+func WithLifecycleEvents(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.lifecycleEvents = enabled
+	}
+}
+
+// WithInflightLimit returns a ManagerOption that limits the number of concurrent fires of
+// the event type t to limit. Further fires block until a running fire of the type completes,
+// e.g. to apply backpressure to FireParallel for a heavy event type.
+// Blocked fires count as running for Wait and InFlight. It panics if limit is not positive.
+//
+// A handler of t firing another event of t with the limit reached blocks forever.
+func WithInflightLimit(t Type, limit int) ManagerOption {
+	if limit <= 0 {
+		panic(fmt.Sprintf("event: WithInflightLimit requires a positive limit, got %d", limit))
+	}
+	return func(m *manager) {
+		if m.limits == nil {
+			m.limits = make(map[Type]chan struct{})
+		}
+		m.limits[t] = make(chan struct{}, limit)
+	}
+}
+
+// WithExpectedTypes returns a ManagerOption that pre-sizes the map of fire stats for n event types
+// to avoid growing it while the types are first fired at startup of large systems. Default is 0.
+// Subscriber lists are kept in shards copied on change, which need no pre-sizing.
+func WithExpectedTypes(n int) ManagerOption {
+	return func(m *manager) {
+		m.expectedTypes = n
+	}
+}
+
+// WithHandlerTracking returns a ManagerOption that enables/disables tracking the start times
+// of running subscriber calls for Manager.StuckHandlers. Tracking adds a small cost to every
+// subscriber call. Default is false.
+func WithHandlerTracking(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.trackHandlers = enabled
+	}
+}
+
+// WithShutdownHook returns a ManagerOption that adds a hook run by Manager.Close after all
+// event handlers completed, e.g. to stop worker pools owned by the event system.
+// Hooks are run in reverse order of adding them. A panicking hook is always recovered and logged,
+// even if disabled by WithRecoverPanic, so the remaining hooks still run.
+func WithShutdownHook(fn func()) ManagerOption {
+	return func(m *manager) {
+		m.shutdownHooks = append(m.shutdownHooks, fn)
+	}
+}
+
+// WithScheduler returns a ManagerOption that sets the func running the background tasks of
+// FireParallel and FireParallelConcurrent instead of new goroutines, e.g. to run handlers on
+// the main thread of a game loop. The scheduler must run every task exactly once, and may run
+// it in the calling goroutine. Subscribers of Fire and FireConcurrent are not affected.
+//
+// Submitted tasks count as in flight from submission, so Wait, Drain and Close block until the
+// scheduler ran them. Calling those from the scheduler goroutine of a single-threaded scheduler
+// with pending tasks therefore deadlocks.
+func WithScheduler(fn func(task func())) ManagerOption {
+	return func(m *manager) {
+		m.scheduler = fn
+	}
+}
+
+// WithWildcardDisabled returns a ManagerOption that disables wildcard subscribers, which receive
+// every fired event and can be a performance trap. Subscribing to the type any(nil), e.g. with
+// SubscribeAll, then panics, and fires skip looking up wildcard subscribers.
+// Firing a nil event calls no subscribers. Default is wildcard subscribers enabled.
+func WithWildcardDisabled() ManagerOption {
+	return func(m *manager) {
+		m.noWildcard = true
+	}
+}
+
+// WithStrictTypes returns a ManagerOption that enables/disables checking before every call of a
+// subscriber subscribed by Manager.Subscribe that the event has the Go type subscribed for.
+// Mismatching events, e.g. of a type sharing the key of WithTypeKeyFunc, are logged as error and
+// not passed to the subscriber, turning failing type assertions into actionable messages.
+// It is meant for development and costs a reflection call per subscriber call. Default is false.
+//
+// Subscribers of the generic helpers like Subscribe already skip mismatching events and are not checked.
+func WithStrictTypes(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.strictTypes = enabled
+	}
+}
+
+// WithWildcardLast returns a ManagerOption that enables/disables calling wildcard subscribers after
+// all typed subscribers instead of before them, e.g. for audit handlers recording the event after
+// all mutations. Default is false, wildcard subscribers are called first.
+func WithWildcardLast(enabled bool) ManagerOption {
+	return func(m *manager) {
+		m.wildcardLast = enabled
+	}
+}
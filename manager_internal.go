@@ -1,9 +1,14 @@
 package event
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 )
@@ -11,22 +16,295 @@ import (
 // manager implements Manager interface.
 type manager struct {
 	activeSubscribers sync.WaitGroup // Wait for all active subscribers
+	inFlight          atomic.Int64   // Number of active fires, mirrors activeSubscribers
+	queued            atomic.Int64   // Number of fires waiting for the scheduler, an inflight limit, Resume or coalescing
+	subscribed        atomic.Int64   // Number of subscribers ever added, including topic subscribers
+	unsubscribed      atomic.Int64   // Number of subscribers ever removed, including topic subscribers
 	log               logr.Logger
+	logValues         []any // Key-value pairs added to log on construction
 	recoverPanic      bool
+	detectDuplicates  bool                             // Warn on subscribing the same handler func twice
+	unifyPtrValue     bool                             // Also fire *T events to T subscribers and vice versa
+	matchEmbedded     bool                             // Also fire embedded struct fields to their subscribers
+	lifecycleEvents   bool                             // Fire SubscriberChanged events
+	expectedTypes     int                              // Number of event types to pre-size maps for
+	limits            map[Type]chan struct{}           // Semaphores limiting concurrent fires per event type
+	typeKey           func(Event) Type                 // Optional func returning the subscriber key of events
+	slowThreshold     time.Duration                    // Duration after which a subscriber call is slow
+	onSlow            func(HandlerInfo, time.Duration) // Optional func called for slow subscriber calls
+	beforeFire        func(Type, Event)                // Optional hook run before every fire
+	afterFire         func(Type, Event)                // Optional hook run after every fire
+	trackHandlers     bool                             // Track running subscriber calls for StuckHandlers
+	shutdownHooks     []func()                         // Hooks run by Close in reverse order
+	closeOnce         sync.Once                        // Close runs once
+	closed            atomic.Bool                      // Whether Close was called, set after dispatching coalesced events
+	postClose         PostClosePolicy                  // Behavior of fires after Close
+	scheduler         func(task func())                // Optional func running FireParallel tasks instead of go
+	noWildcard        bool                             // Panic on wildcard subscriptions and skip their lookup
+	metrics           []MetricsRecorder                // Recorders of fire metrics
+	strictTypes       bool                             // Check the Go type of events passed to untyped subscribers
+	wildcardLast      bool                             // Call wildcard subscribers after typed subscribers
+	panicHandler      func(*PanicError)                // Optional func called with recovered subscriber panics
+	clock             Clock                            // Source of time
+	envelope          bool                             // Record an Envelope for every fire
+	envelopeID        func() string                    // Generates the ids of envelopes
+	middleware        []Middleware                     // Wrap every subscriber call, outermost first
+	dispatchTrace     func(t Type, order []int)        // Optional func called with the order of subscribers after each fire
+	panicIsolation    bool                             // Re-panic the first unrecovered subscriber panic after the fire
+	enforceDeadline   bool                             // Skip subscribers once the deadline of the fire's context passed
 
-	mu          sync.RWMutex             // Protects following fields
-	subscribers map[Type]*subscriberList // Event type to subscribers
+	mu          sync.Mutex // Serializes changes of the subscribers
+	subscribers typeLists  // Event type to subscribers, changed under mu only
+	subSeq      uint64     // Order of subscription of the last subscriber, protected by mu
+
+	idle atomic.Pointer[chan struct{}] // Closed once no fire is in flight, shared by Drain calls
+
+	statesMu sync.RWMutex        // Protects following fields
+	states   map[Type]*typeState // Event type to state of fired events
+
+	typesMu    sync.RWMutex    // Protects following fields
+	typeNames  map[Type]string // Registered event type to name
+	namedTypes map[string]Type // Registered name to event type
+
+	runningMu sync.Mutex                // Protects following fields
+	running   map[*HandlerInfo]struct{} // Running subscriber calls if tracked
+
+	pausedTypes atomic.Int32          // Number of paused event types, 0 skips pauseMu
+	pauseBuffer int                   // Maximum number of events buffered per paused type
+	pauseMu     sync.Mutex            // Protects following fields
+	paused      map[Type]*pauseBuffer // Paused event type to buffered events
+
+	replays  map[Type]*replayBuffer // Event type to retained events, not modified after New
+	replayMu sync.Mutex             // Protects the replay buffers
+
+	coalescers map[Type]*coalescer // Coalesced event type to pending fire, not modified after New
+
+	topics topics // Topic subscribers
+
+	draining atomic.Int32        // Number of running UnsubscribeAllAndWait calls, 0 skips drainMu
+	drainMu  sync.Mutex          // Protects following fields
+	drains   map[*drain]struct{} // Running UnsubscribeAllAndWait calls
+
+	panicsUsed   atomic.Bool     // Whether PanicChan was called, skips sending panics if not
+	panicsMu     sync.Mutex      // Protects following fields
+	panics       chan PanicError // Recovered panics for PanicChan
+	panicsClosed bool            // Whether panics is closed by Close
 }
 
 type subscriberList struct {
-	subs []*subscriber  // Subscribers sorted by priority
-	wg   sync.WaitGroup // Wait for active subscribers in list
+	subs  atomic.Pointer[subscriberSlice] // Subscribers sorted by priority, replaced on change and never mutated in place
+	wg    sync.WaitGroup                  // Wait for active subscribers in list
+	first subscriberSlice                 // Inline storage of the first subscriber, never mutated once replaced
+}
+
+// subscriberSlice is a slice of subscribers with inline storage for a single subscriber,
+// so the common case of event types with one subscriber needs no separate slice allocation.
+type subscriberSlice struct {
+	subs []*subscriber
+	one  [1]*subscriber
+}
+
+// set sets the subscribers, using the inline storage for a single subscriber.
+func (s *subscriberSlice) set(subs []*subscriber) {
+	if len(subs) == 1 {
+		s.one[0] = subs[0]
+		subs = s.one[:]
+	}
+	s.subs = subs
+}
+
+// newSubscriberList returns a new list with the subscriber stored inline,
+// which allocates the list only.
+func newSubscriberList(sub *subscriber) *subscriberList {
+	list := new(subscriberList)
+	list.first.one[0] = sub
+	list.first.subs = list.first.one[:]
+	list.subs.Store(&list.first)
+	return list
+}
+
+// load returns the current subscribers of the list.
+func (l *subscriberList) load() []*subscriber {
+	if s := l.subs.Load(); s != nil {
+		return s.subs
+	}
+	return nil
+}
+
+// store replaces the subscribers of the list. The caller must hold m.mu.
+func (l *subscriberList) store(subs []*subscriber) {
+	s := new(subscriberSlice)
+	s.set(subs)
+	l.subs.Store(s)
+}
+
+// listShards is the number of shards of typeLists.
+const listShards = 64
+
+// typeLists maps event types to their subscriber lists. The map is split into shards that are
+// replaced on change and never mutated in place, so fires look up lists without locking, while
+// adding or removing a type copies a single shard only, e.g. when registering thousands of types.
+type typeLists struct {
+	shards [listShards]atomic.Pointer[map[Type]*subscriberList]
+	types  atomic.Int64 // Number of types with a list
+}
+
+// shardOf returns the shard of the event type, derived from the address of reflect types.
+// Other implementations of Type share the first shard.
+func shardOf(t Type) int {
+	if v := reflect.ValueOf(t); v.Kind() == reflect.Pointer {
+		return int(v.Pointer()>>4) % listShards
+	}
+	return 0
+}
+
+// get returns the subscriber list of the event type or nil if it has none.
+func (l *typeLists) get(t Type) *subscriberList {
+	if shard := l.shards[shardOf(t)].Load(); shard != nil {
+		return (*shard)[t]
+	}
+	return nil
+}
+
+// len returns the number of event types with a subscriber list.
+func (l *typeLists) len() int {
+	return int(l.types.Load())
+}
+
+// set sets the subscriber list of the event type, or removes it if list is nil,
+// by replacing a copy of its shard. The caller must hold m.mu.
+func (l *typeLists) set(t Type, list *subscriberList) {
+	i := shardOf(t)
+	var old map[Type]*subscriberList
+	if shard := l.shards[i].Load(); shard != nil {
+		old = *shard
+	}
+	lists := make(map[Type]*subscriberList, len(old)+1)
+	for t, l := range old {
+		lists[t] = l
+	}
+	_, existed := lists[t]
+	if list == nil {
+		delete(lists, t)
+	} else {
+		lists[t] = list
+	}
+	switch {
+	case existed && list == nil:
+		l.types.Add(-1)
+	case !existed && list != nil:
+		l.types.Add(1)
+	}
+	l.shards[i].Store(&lists)
+}
+
+// all returns a snapshot of the subscriber lists by event type.
+func (l *typeLists) all() map[Type]*subscriberList {
+	lists := make(map[Type]*subscriberList, l.len())
+	for i := range l.shards {
+		if shard := l.shards[i].Load(); shard != nil {
+			for t, list := range *shard {
+				lists[t] = list
+			}
+		}
+	}
+	return lists
+}
+
+// clear removes all subscriber lists and returns them by event type. The caller must hold m.mu.
+func (l *typeLists) clear() map[Type]*subscriberList {
+	lists := l.all()
+	for i := range l.shards {
+		l.shards[i].Store(nil)
+	}
+	l.types.Store(0)
+	return lists
+}
+
+// typeState is the state of fired events of a type.
+type typeState struct {
+	fired     atomic.Int64 // Number of fired events
+	lastFired atomic.Int64 // Unix nano time the last event was fired
+	active    activity     // Running fires of the type, independent of subscriber lists
+}
+
+// activity counts running operations to wait for none to be running.
+// Unlike sync.WaitGroup, operations may start concurrently with waiting.
+type activity struct {
+	mu   sync.Mutex
+	n    int           // Number of running operations
+	idle chan struct{} // Closed when n drops to 0, nil if n is 0
+}
+
+func (a *activity) start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.n == 0 {
+		a.idle = make(chan struct{})
+	}
+	a.n++
+}
+
+func (a *activity) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.n--
+	if a.n == 0 {
+		close(a.idle)
+		a.idle = nil
+	}
+}
+
+// wait blocks until no operation is running.
+func (a *activity) wait() {
+	a.mu.Lock()
+	idle := a.idle
+	a.mu.Unlock()
+	if idle != nil {
+		<-idle
+	}
 }
 
 // subscriber is a subscriber to an event.
 type subscriber struct {
-	priority int         // The higher the priority, the earlier the subscriber is called.
-	fn       HandlerFunc // The event handler func.
+	priority  int                          // The higher the priority, the earlier the subscriber is called.
+	fn        HandlerFunc                  // The event handler func.
+	metaFn    func(Event, Meta)            // Optional handler called with the metadata of the fire instead of fn.
+	ctxFn     func(context.Context, Event) // Optional handler called with the context of the fire instead of fn.
+	handler   any                          // The original handler func wrapped by fn, used for identity comparison.
+	name      string                       // Optional name used in logs instead of the priority only.
+	origin    *subscriber                  // The subscriber this is a re-prioritized copy of, nil if none.
+	expected  Type                         // The Go type of events fn expects with strict types, nil if unchecked.
+	tags      []string                     // Optional tags matched by FireTagged.
+	after     []string                     // Optional names of subscribers to be called before, see SubscribeAfter.
+	cleanup   bool                         // Whether the subscriber runs after all others, see SubscribeCleanup.
+	seq       uint64                       // Order of subscription within the manager.
+	eventType Type                         // The event type subscribed to, set when subscribing.
+}
+
+// identity returns the subscriber identifying s across priority changes.
+func (s *subscriber) identity() *subscriber {
+	if s.origin != nil {
+		return s.origin
+	}
+	return s
+}
+
+// before reports whether s is called before o by order of priority.
+// Cleanup subscribers sort after all other subscribers.
+func (s *subscriber) before(o *subscriber) bool {
+	if s.cleanup != o.cleanup {
+		return o.cleanup
+	}
+	return s.priority > o.priority
+}
+
+// logValues returns the key-value pairs identifying the subscriber in logs.
+func (s *subscriber) logValues() []any {
+	if s.name == "" {
+		return []any{"subscriberPriority", s.priority}
+	}
+	return []any{"subscriberName", s.name, "subscriberPriority", s.priority}
 }
 
 func (m *manager) Wait(events ...Event) {
@@ -35,118 +313,564 @@ func (m *manager) Wait(events ...Event) {
 		return
 	}
 
-	m.mu.RLock()
-	subs := m.subscribers
-	m.mu.RUnlock()
-
 	for _, event := range events {
-		eventType := typeOf(event)
-		list, ok := subs[eventType]
+		eventType := m.typeOf(event)
+		if eventType == wildcardKey {
+			if list := m.list(wildcardKey); list != nil {
+				list.wg.Wait()
+			}
+		}
+		m.statesMu.RLock()
+		state, ok := m.states[eventType]
+		m.statesMu.RUnlock()
 		if ok {
-			list.wg.Wait()
+			state.active.wait()
+		}
+	}
+}
+
+func (m *manager) Drain(ctx context.Context) error {
+	idle := m.idleChan()
+	// Checked after sharing the channel, so exit either closes it or no fire is in flight
+	if m.inFlight.Load() == 0 {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d fires still in flight: %w", m.InFlight(), ctx.Err())
+	}
+}
+
+// idleChan returns a channel closed once no fire is in flight,
+// shared by all callers until then instead of a goroutine per caller.
+func (m *manager) idleChan() <-chan struct{} {
+	for {
+		if idle := m.idle.Load(); idle != nil {
+			return *idle
+		}
+		idle := make(chan struct{})
+		if m.idle.CompareAndSwap(nil, &idle) {
+			return idle
+		}
+	}
+}
+
+func (m *manager) Close() error {
+	m.closeOnce.Do(func() {
+		m.closeCoalescers()
+		m.closed.Store(true)
+		m.activeSubscribers.Wait()
+		m.closePanics()
+		for i := len(m.shutdownHooks) - 1; i >= 0; i-- {
+			m.callShutdownHook(m.shutdownHooks[i])
+		}
+	})
+	return nil
+}
+
+// callShutdownHook runs a shutdown hook, recovering any panic.
+func (m *manager) callShutdownHook(hook func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.log.Error(nil, "recovered from panic by a shutdown hook", "panic", r)
 		}
+	}()
+	hook()
+}
+
+func (m *manager) InFlight() int {
+	return int(m.inFlight.Load())
+}
+
+func (m *manager) QueueDepth() int {
+	return int(m.queued.Load())
+}
+
+func (m *manager) RegistrationStats() (subscribed, unsubscribed int64) {
+	return m.subscribed.Load(), m.unsubscribed.Load()
+}
+
+func (m *manager) Stats(event Event) (fired int64, lastFired time.Time) {
+	m.statesMu.RLock()
+	state, ok := m.states[m.typeOf(event)]
+	m.statesMu.RUnlock()
+	if !ok || state.fired.Load() == 0 {
+		return 0, time.Time{}
 	}
+	return state.fired.Load(), time.Unix(0, state.lastFired.Load())
+}
+
+// state returns the state of the event type and creates it if it does not exist.
+func (m *manager) state(eventType Type) *typeState {
+	m.statesMu.RLock()
+	state, ok := m.states[eventType]
+	m.statesMu.RUnlock()
+	if ok {
+		return state
+	}
+	m.statesMu.Lock()
+	defer m.statesMu.Unlock()
+	state, ok = m.states[eventType]
+	if !ok {
+		state = new(typeState)
+		m.states[eventType] = state
+	}
+	return state
 }
 
 func (m *manager) HasSubscriber(events ...Event) bool {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	lists := &m.subscribers
 	if len(events) == 0 {
-		return len(m.subscribers) != 0
+		return lists.len() != 0
 	}
-	if m.subscribers[anyType] != nil {
+	if lists.get(wildcardKey) != nil {
 		return true
 	}
 	for _, event := range events {
-		if m.subscribers[typeOf(event)] != nil {
+		if m.hasTypedSubscriber(lists, m.typeOf(event)) {
 			return true
 		}
 	}
 	return false
 }
 
-func (m *manager) UnsubscribeAll(events ...Event) int {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	var count int
+func (m *manager) SubscriberCount(events ...Event) (count int) {
+	lists := &m.subscribers
 	if len(events) == 0 {
-		for _, list := range m.subscribers {
-			count += len(list.subs)
+		for _, list := range lists.all() {
+			count += len(list.load())
 		}
-		m.subscribers = make(map[Type]*subscriberList)
+		m.topics.mu.RLock()
+		count += m.topics.root.count()
+		m.topics.mu.RUnlock()
 		return count
 	}
-
 	for _, event := range events {
-		eventType := typeOf(event)
-		list, ok := m.subscribers[eventType]
-		if !ok {
-			continue
+		if list := lists.get(m.typeOf(event)); list != nil {
+			count += len(list.load())
 		}
-		count += len(list.subs)
-		delete(m.subscribers, eventType)
 	}
 	return count
 }
 
-func (m *manager) Subscribe(eventType Event, priority int, fn HandlerFunc) (unsubscribe func()) {
-	return m.subscribe(typeOf(eventType), priority, fn)
+func (m *manager) HasSubscriberType(t Type) bool {
+	if t == nil {
+		t = wildcardKey
+	}
+	lists := &m.subscribers
+	return lists.get(wildcardKey) != nil || m.hasTypedSubscriber(lists, t)
+}
+
+// hasTypedSubscriber reports whether an event of the type would be fired to any non-wildcard subscriber,
+// also considering pointer and value unification.
+func (m *manager) hasTypedSubscriber(lists *typeLists, eventType Type) bool {
+	if lists.get(eventType) != nil {
+		return true
+	}
+	if m.unifyPtrValue {
+		if t := unifiedType(eventType); t != nil && lists.get(t) != nil {
+			return true
+		}
+	}
+	if m.matchEmbedded {
+		return hasEmbeddedSubscriber(lists, eventType, eventType.Kind() == reflect.Pointer, 1)
+	}
+	return false
+}
+
+// hasEmbeddedSubscriber reports whether fireEmbeddedFields would call any subscriber of the struct
+// types embedded by the event type, up to maxEmbedDepth. Like there, subscribers of *T only match
+// an embedded T if the struct is addressable, i.e. reached through a pointer.
+func hasEmbeddedSubscriber(lists *typeLists, eventType Type, addressable bool, depth int) bool {
+	if depth > maxEmbedDepth {
+		return false
+	}
+	for _, f := range embeddedFields(eventType) {
+		ft, fieldAddressable := f.Type, addressable
+		if ft.Kind() == reflect.Pointer {
+			if lists.get(ft) != nil {
+				return true
+			}
+			ft, fieldAddressable = ft.Elem(), true
+		} else if addressable && lists.get(reflect.PointerTo(ft)) != nil {
+			return true
+		}
+		if lists.get(ft) != nil || hasEmbeddedSubscriber(lists, ft, fieldAddressable, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// unifiedType returns the value type of a pointer type or the pointer type of a value type.
+func unifiedType(eventType Type) Type {
+	switch {
+	case eventType == nil:
+		return nil
+	case eventType.Kind() == reflect.Pointer:
+		return eventType.Elem()
+	default:
+		return reflect.PointerTo(eventType)
+	}
+}
+
+func (m *manager) UnsubscribeAll(events ...Event) int {
+	count, _ := m.unsubscribeAll(events, nil)
+	return count
+}
+
+func (m *manager) UnsubscribeAllAndWait(events ...Event) (int, error) {
+	d := &drain{subs: make(map[*subscriber]struct{})}
+	count, removed := m.unsubscribeAll(events, d)
+	defer m.stopDrain(d)
+	for _, list := range removed {
+		list.wg.Wait()
+	}
+	return count, d.err()
 }
-func (m *manager) subscribe(eventType Type, priority int, fn HandlerFunc) (unsubscribe func()) {
+
+// unsubscribeAll removes the subscriber lists of the events, or all if no events are specified,
+// and returns the number of subscribers unsubscribed and the removed lists by event type.
+// If d is not nil, it is started for the subscribers of the lists before removing them.
+func (m *manager) unsubscribeAll(events []Event, d *drain) (count int, removed map[Type]*subscriberList) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	if len(events) == 0 {
+		removed = m.subscribers.all()
+	} else {
+		removed = make(map[Type]*subscriberList, len(events))
+		for _, event := range events {
+			eventType := m.typeOf(event)
+			if list := m.list(eventType); list != nil {
+				removed[eventType] = list
+			}
+		}
+	}
+	if d != nil {
+		// Started first so no panic of a subscriber call running at removal is missed
+		m.startDrain(d, removed)
+	}
+	if len(events) == 0 {
+		m.subscribers.clear()
+	} else {
+		for eventType := range removed {
+			m.subscribers.set(eventType, nil)
+		}
+	}
+	m.mu.Unlock()
+	if len(events) == 0 {
+		count += m.topics.clear()
+	}
+
+	for eventType, list := range removed {
+		count += len(list.load())
+		m.subscriberChanged(eventType, 0)
+	}
+	m.unsubscribed.Add(int64(count))
+	return count, removed
+}
+
+func (m *manager) RegisterType(name string, sample Event) {
+	eventType := m.typeOf(sample)
+	m.typesMu.Lock()
+	defer m.typesMu.Unlock()
+	if old, ok := m.namedTypes[name]; ok && m.typeNames[old] == name {
+		delete(m.typeNames, old)
+	}
+	m.namedTypes[name] = eventType
+	m.typeNames[eventType] = name
+}
+
+func (m *manager) TypeByName(name string) (Type, bool) {
+	m.typesMu.RLock()
+	defer m.typesMu.RUnlock()
+	t, ok := m.namedTypes[name]
+	return t, ok
+}
+
+func (m *manager) FireJSON(name string, data []byte) error {
+	eventType, ok := m.TypeByName(name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownType, name)
+	}
+
+	// Decode into a new value of the registered type, or of its element type
+	// if a pointer type is registered.
+	isPtr := eventType.Kind() == reflect.Pointer
+	elemType := eventType
+	if isPtr {
+		elemType = eventType.Elem()
+	}
+	v := reflect.New(elemType)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return fmt.Errorf("error decoding event %q: %w", name, err)
+	}
+	if !isPtr {
+		v = v.Elem()
+	}
+
+	m.Fire(v.Interface())
+	return nil
+}
 
+// typeName returns the registered name of the event type
+// or falls back to the reflect.Type string.
+func (m *manager) typeName(eventType Type) string {
+	m.typesMu.RLock()
+	name, ok := m.typeNames[eventType]
+	m.typesMu.RUnlock()
+	if ok {
+		return name
+	}
+	if eventType == nil || eventType == wildcardKey {
+		return "<nil>"
+	}
+	return eventType.String()
+}
+
+func (m *manager) Reset() {
+	var removed int
+	m.mu.Lock()
+	for _, list := range m.subscribers.clear() {
+		removed += len(list.load())
+	}
+	m.mu.Unlock()
+
+	// Keep the states to let Wait cover fires running across the reset, only clear their stats
+	m.statesMu.RLock()
+	for _, state := range m.states {
+		state.fired.Store(0)
+		state.lastFired.Store(0)
+	}
+	m.statesMu.RUnlock()
+
+	m.resetReplays()
+	m.resetCoalescers()
+	m.resetPaused()
+
+	removed += m.topics.clear()
+	m.unsubscribed.Add(int64(removed))
+}
+
+func (m *manager) Subscribe(eventType Event, priority int, fn HandlerFunc) (unsubscribe func()) {
 	sub := &subscriber{
 		priority: priority,
 		fn:       fn,
+		handler:  fn,
+	}
+	if m.strictTypes {
+		sub.expected = typeOf(eventType)
 	}
+	return m.subscribe(m.typeOf(eventType), sub)
+}
 
-	// Get-add subscriber list for event type
-	list, ok := m.subscribers[eventType]
-	if ok {
-		list.subs = append(list.subs, sub)
-		// Sort subscribers by priority
-		sort.Slice(list.subs, func(i, j int) bool {
-			return list.subs[i].priority > list.subs[j].priority
-		})
-	} else {
-		m.subscribers[eventType] = &subscriberList{subs: []*subscriber{sub}}
+// asManager returns the manager created by New that mgr is, or that it wraps if it has an
+// Unwrap() Manager method like eventtest.Recorder, so helpers can use the internals of wrappers.
+// Wrappers should only implement Unwrap if firing and subscribing on the wrapped manager
+// is equivalent to doing so on the wrapper.
+func asManager(mgr any) (*manager, bool) {
+	for {
+		switch v := mgr.(type) {
+		case *manager:
+			return v, true
+		case interface{ Unwrap() Manager }:
+			mgr = v.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// subscribe subscribes sub to the event type using the internals of mgr if possible
+// and falls back to Subscriber.Subscribe otherwise.
+func subscribe(mgr Subscriber, eventType Event, sub *subscriber) (unsubscribe func()) {
+	if m, ok := asManager(mgr); ok {
+		return m.subscribe(m.typeOf(eventType), sub)
+	}
+	return mgr.Subscribe(eventType, sub.priority, sub.fn)
+}
+
+func (m *manager) subscribe(eventType Type, sub *subscriber) (unsubscribe func()) {
+	if m.noWildcard && eventType == wildcardKey {
+		panic("event: wildcard subscribers are disabled by WithWildcardDisabled")
 	}
+	count := m.insert(eventType, sub)
+	m.subscriberChanged(eventType, count)
 
 	// Unsubscribe func
 	var once sync.Once
 	return func() { once.Do(func() { m.unsubscribe(eventType, sub) }) }
 }
 
-func (m *manager) unsubscribe(eventType Type, sub *subscriber) {
+// insert adds sub to the subscriber list of the event type
+// and returns the new number of subscribers of the type.
+func (m *manager) insert(eventType Type, sub *subscriber) int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	list, ok := m.subscribers[eventType]
-	if !ok {
+
+	if m.detectDuplicates {
+		m.warnDuplicate(eventType, sub)
+	}
+	m.subSeq++
+	sub.seq, sub.eventType = m.subSeq, eventType
+	m.subscribed.Add(1)
+
+	// Get-add subscriber list for event type
+	list := m.list(eventType)
+	if list != nil {
+		list.store(m.ordered(eventType, insertSorted(list.load(), sub)))
+	} else {
+		list = newSubscriberList(sub)
+		m.subscribers.set(eventType, list)
+	}
+	return len(list.load())
+}
+
+// insertSorted returns a copy of subs with sub inserted after all subscribers with a higher or
+// equal priority, keeping the order of subscription for equal priorities.
+// Copying on write lets running fires iterate the old slice.
+func insertSorted(subs []*subscriber, sub *subscriber) []*subscriber {
+	i := sort.Search(len(subs), func(i int) bool {
+		return sub.before(subs[i])
+	})
+	n := make([]*subscriber, 0, len(subs)+1)
+	n = append(n, subs[:i]...)
+	n = append(n, sub)
+	return append(n, subs[i:]...)
+}
+
+// subscriberChanged fires a SubscriberChanged event if lifecycle events are enabled.
+func (m *manager) subscriberChanged(eventType Type, count int) {
+	if !m.lifecycleEvents || eventType == m.typeOf(&SubscriberChanged{}) {
 		return
 	}
-	if len(list.subs) == 1 {
-		delete(m.subscribers, eventType)
+	m.Fire(&SubscriberChanged{Type: exportedType(eventType), Count: count})
+}
+
+// warnDuplicate logs a warning if the handler of sub is already subscribed to the event type.
+func (m *manager) warnDuplicate(eventType Type, sub *subscriber) {
+	list := m.list(eventType)
+	if list == nil {
 		return
 	}
-	for i, s := range list.subs {
-		if s != sub { // Find by pointer
+	for _, s := range list.load() {
+		if sameFunc(s.handler, sub.handler) {
+			m.log.WithValues(sub.logValues()...).Info("likely duplicate subscription of the same handler func",
+				"eventType", m.typeName(eventType),
+				"existingPriority", s.priority)
+			return
+		}
+	}
+}
+
+// sameFunc reports whether a and b are funcs with the same code pointer.
+func sameFunc(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Func && vb.Kind() == reflect.Func &&
+		va.Pointer() == vb.Pointer()
+}
+
+func (m *manager) unsubscribe(eventType Type, sub *subscriber) {
+	if count, ok := m.remove(eventType, sub); ok {
+		m.subscriberChanged(eventType, count)
+	}
+}
+
+// remove removes sub from the subscriber list of the event type and returns
+// the new number of subscribers of the type and whether sub was removed.
+func (m *manager) remove(eventType Type, sub *subscriber) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.removeLocked(eventType, sub)
+}
+
+// removeFunc removes the first subscriber of the event type subscribed with the same
+// handler func and returns whether a subscriber was removed.
+func (m *manager) removeFunc(eventType Type, handler any) bool {
+	m.mu.Lock()
+	var count int
+	var removed bool
+	if list := m.list(eventType); list != nil {
+		for _, sub := range list.load() {
+			if sameFunc(sub.handler, handler) {
+				count, removed = m.removeLocked(eventType, sub)
+				break
+			}
+		}
+	}
+	m.mu.Unlock()
+	if removed {
+		m.subscriberChanged(eventType, count)
+	}
+	return removed
+}
+
+// removeLocked is like remove but the caller must hold m.mu.
+func (m *manager) removeLocked(eventType Type, sub *subscriber) (int, bool) {
+	list := m.list(eventType)
+	if list == nil {
+		return 0, false
+	}
+	subs := list.load()
+	for i, s := range subs {
+		if s.identity() != sub.identity() { // Find by pointer
 			continue
 		}
-		// Delete subscriber from list while maintaining the order.
-		copy(list.subs[i:], list.subs[i+1:]) // Shift list[i+1:] left one index.
-		list.subs[len(list.subs)-1] = nil    // Erase last element (write zero value).
-		list.subs = list.subs[:len(list.subs)-1]
-		return
+		m.unsubscribed.Add(1)
+		if len(subs) == 1 {
+			m.subscribers.set(eventType, nil)
+			return 0, true
+		}
+		// Delete subscriber from a copy of the list and recompute the order of dependencies,
+		// running fires may still iterate the old slice.
+		removed := make([]*subscriber, 0, len(subs)-1)
+		removed = append(removed, subs[:i]...)
+		list.store(m.ordered(eventType, append(removed, subs[i+1:]...)))
+		return len(subs) - 1, true
 	}
+	return len(subs), false
 }
 
 func (m *manager) FireParallel(event Event, after ...HandlerFunc) {
-	m.activeSubscribers.Add(1)
-	go func() {
-		defer m.activeSubscribers.Done()
-		m.fire(event)
+	m.fireParallel(event, nil, after, nil)
+}
+
+func (m *manager) FireParallelConcurrent(event Event, after ...HandlerFunc) {
+	m.fireParallel(event, &fireOptions{concurrent: true}, after, nil)
+}
+
+func (m *manager) FireParallelHandle(event Event, after ...HandlerFunc) *FireHandle {
+	h := &FireHandle{done: make(chan struct{})}
+	m.fireParallel(event, nil, after, func() { close(h.done) })
+	return h
+}
+
+// fireParallel fires the event in a new goroutine and runs the after funcs when done.
+// The optional done func is called last, even if an after func panicked.
+func (m *manager) fireParallel(event Event, opts *fireOptions, after []HandlerFunc, done func()) {
+	// Decide on a fire after Close in the firing goroutine, so fires before Close are dispatched
+	rejected := m.rejectClosed(event)
+	o := fireOptions{admitted: true}
+	if opts != nil {
+		o = *opts
+		o.admitted = true
+	}
+	opts = &o
+	m.enter()
+	// Mark the type as active right away until the after funcs are done,
+	// so that waiting for the event covers the whole parallel fire.
+	state := m.state(m.typeOf(event))
+	state.active.start()
+	m.goFunc(func() {
+		defer m.exit()
+		defer state.active.stop()
+		if done != nil {
+			defer done()
+		}
+		if !rejected {
+			m.fire(event, opts)
+		}
 
 		var i int
 		if m.recoverPanic {
@@ -155,7 +879,7 @@ func (m *manager) FireParallel(event Event, after ...HandlerFunc) {
 					m.log.Error(nil,
 						"recovered from panic by an 'after fire' func",
 						"panic", r,
-						"eventType", typeOf(event),
+						"eventType", m.typeName(m.typeOf(event)),
 						"index", i)
 				}
 			}()
@@ -165,55 +889,545 @@ func (m *manager) FireParallel(event Event, after ...HandlerFunc) {
 		for i, fn = range after {
 			fn(event)
 		}
-	}()
+	})
+}
+
+// goFunc runs the task with the scheduler if set and in a new goroutine otherwise.
+func (m *manager) goFunc(task func()) {
+	if m.scheduler != nil {
+		m.queued.Add(1)
+		m.scheduler(func() {
+			m.queued.Add(-1)
+			task()
+		})
+		return
+	}
+	go task()
 }
 
 func (m *manager) Fire(event Event) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, nil)
+}
+
+func (m *manager) FireCtx(ctx context.Context, event Event) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{ctx: ctx})
+}
+
+func (m *manager) FireTo(event Event, match func(Subscription) bool) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{filter: func(sub *subscriber) bool {
+		return match(Subscription{m: m, eventType: sub.eventType, sub: sub.identity()})
+	}})
+}
+
+func (m *manager) FireRange(event Event, minPriority, maxPriority int) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{filter: func(sub *subscriber) bool {
+		return sub.priority >= minPriority && sub.priority <= maxPriority
+	}})
+}
+
+// fireEach fires the event like Fire and calls each after every called subscriber.
+func (m *manager) fireEach(event Event, each func(Event)) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{afterEach: each})
+}
+
+func (m *manager) FireTagged(event Event, tagFilter func(tags []string) bool) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{filter: func(sub *subscriber) bool {
+		return tagFilter(sub.tags)
+	}})
+}
+
+func (m *manager) FireNoRecover(event Event) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{noRecover: true})
+}
+
+func (m *manager) FireNoWildcard(event Event) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{noWildcard: true})
+}
+
+func (m *manager) FireConcurrent(event Event) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{concurrent: true})
+}
+
+// enter marks a fire as active until exit is called.
+func (m *manager) enter() {
 	m.activeSubscribers.Add(1)
-	defer m.activeSubscribers.Done()
-	m.fire(event)
+	m.inFlight.Add(1)
+}
+
+// exit marks a fire entered with enter as done.
+func (m *manager) exit() {
+	if m.inFlight.Add(-1) == 0 && m.idle.Load() != nil {
+		if idle := m.idle.Swap(nil); idle != nil {
+			close(*idle)
+		}
+	}
+	m.activeSubscribers.Done()
+}
+
+// wildcard is the type of wildcardKey.
+type wildcard struct{}
+
+// wildcardKey is the subscriber key of wildcard subscribers, which subscribe to the nil type.
+// An explicit key avoids nil map keys and lets firing a nil event reach wildcard subscribers once.
+var wildcardKey Type = reflect.TypeOf(wildcard{})
+
+// exportedType returns the type to expose to users for a subscriber key, which is nil for wildcardKey.
+func exportedType(eventType Type) Type {
+	if eventType == wildcardKey {
+		return nil
+	}
+	return eventType
+}
+
+func (m *manager) FireLazy(eventType Event, build func() Event) {
+	m.enter()
+	defer m.exit()
+
+	typ := m.typeOf(eventType)
+	lists := &m.subscribers
+	list, anyList := typedList(lists, typ), lists.get(wildcardKey)
+	subscribed := anyList != nil || m.hasTypedSubscriber(lists, typ)
+	if !subscribed {
+		return
+	}
+	m.dispatch(build(), typ, list, anyList, nil)
 }
 
-var anyType = typeOf(any(nil))
+// fireOptions customizes a single fire.
+type fireOptions struct {
+	ctx         context.Context        // The context of the fire, may be nil
+	filter      func(*subscriber) bool // Only call subscribers the filter returns true for
+	concurrent  bool                   // Call all subscribers concurrently
+	afterEach   func(Event)            // Optional func called after each subscriber in order
+	unpaused    bool                   // Dispatch even if the event type is paused
+	pinned      *subscriberList        // Optional list to call pinnedSubs of instead of its current subscribers
+	pinnedSubs  []*subscriber          // Subscribers of pinned at the time of recording the fire
+	noWildcard  bool                   // Skip wildcard subscribers
+	noRecover   bool                   // Let subscriber panics propagate regardless of recoverPanic
+	uncoalesced bool                   // Dispatch even if the event type is coalesced
+	refire      bool                   // Re-dispatch of a retained event, which is not retained again
+	onPanic     func(*PanicError)      // Optional func called with every recovered subscriber panic, recovers regardless of recoverPanic
+	meta        Meta                   // The metadata passed to subscribers of SubscribeMeta
+	admitted    bool                   // Fired before Close, dispatched even if closed meanwhile
+	order       *dispatchOrder         // Records the priorities of called subscribers for WithDispatchTrace
+	isolated    *isolatedPanic         // Holds the first unrecovered subscriber panic for WithPanicIsolation
+}
+
+// match reports whether the subscriber should be called.
+func (o *fireOptions) match(sub *subscriber) bool {
+	return o == nil || o.filter == nil || o.filter(sub)
+}
+
+// fire fires the event to its subscribers. The options may be nil.
+func (m *manager) fire(event Event, opts *fireOptions) {
+	eventType := m.typeOf(event)
+	list, anyList := m.lists(eventType)
+	if opts != nil && opts.noWildcard {
+		anyList = nil
+	}
+	m.dispatch(event, eventType, list, anyList, opts)
+}
 
-func (m *manager) fire(event Event) {
-	eventType := typeOf(event)
+// lists returns the subscriber list of the event type and the wildcard list.
+func (m *manager) lists(eventType Type) (list, anyList *subscriberList) {
+	lists := &m.subscribers
+	if m.noWildcard {
+		return typedList(lists, eventType), nil
+	}
+	return typedList(lists, eventType), lists.get(wildcardKey)
+}
 
-	m.mu.RLock()
-	list := m.subscribers[eventType]
-	anyList := m.subscribers[anyType]
-	m.mu.RUnlock()
+// typedList returns the subscriber list of the event type, which is nil for wildcardKey
+// since wildcard subscribers are always called anyway.
+func typedList(lists *typeLists, eventType Type) *subscriberList {
+	if eventType == wildcardKey {
+		return nil
+	}
+	return lists.get(eventType)
+}
 
-	m.fireSubscribers(event, anyList)
-	m.fireSubscribers(event, list)
+// list returns the subscriber list of the event type without locking,
+// so fires never wait for subscribing, unsubscribing fires or other fires.
+func (m *manager) list(eventType Type) *subscriberList {
+	return m.subscribers.get(eventType)
 }
 
-func (m *manager) fireSubscribers(event Event, list *subscriberList) {
+// dispatch calls the hooks and subscribers of both lists with the event.
+func (m *manager) dispatch(event Event, eventType Type, list, anyList *subscriberList, opts *fireOptions) {
+	if (opts == nil || !opts.admitted) && m.rejectClosed(event) {
+		return
+	}
+	if m.buffer(event, eventType, opts) || m.coalesce(event, eventType, opts) {
+		return
+	}
+	if sem := m.limits[eventType]; sem != nil {
+		m.queued.Add(1)
+		sem <- struct{}{}
+		m.queued.Add(-1)
+		defer func() { <-sem }()
+	}
+
+	if l, subs, ok := m.record(event, eventType, opts); ok {
+		var o fireOptions
+		if opts != nil {
+			o = *opts
+		}
+		o.pinned, o.pinnedSubs = l, subs
+		list, opts = l, &o
+	}
+
+	if m.envelope {
+		opts = m.withEnvelope(opts)
+	}
+	if m.dispatchTrace != nil {
+		opts = withDispatchOrder(opts)
+	}
+	if m.panicIsolation && !m.recoverPanic && (opts == nil || !opts.noRecover) {
+		opts = withIsolatedPanic(opts)
+	}
+	trace(event, opts)
+	start := m.clock.Now()
+	state := m.state(eventType)
+	state.active.start()
+	defer state.active.stop()
+	state.fired.Add(1)
+	state.lastFired.Store(start.UnixNano())
+
+	m.callHook("before fire", m.beforeFire, eventType, event)
+	if opts != nil && opts.concurrent {
+		var wg sync.WaitGroup
+		m.fireSubscribers(event, anyList, opts, &wg)
+		m.fireSubscribers(event, list, opts, &wg)
+		m.fireUnified(event, opts, &wg)
+		m.fireEmbedded(event, opts, &wg)
+		wg.Wait()
+	} else {
+		if !m.wildcardLast {
+			m.fireSubscribers(event, anyList, opts, nil)
+		}
+		m.fireSubscribers(event, list, opts, nil)
+		m.fireUnified(event, opts, nil)
+		m.fireEmbedded(event, opts, nil)
+		if m.wildcardLast {
+			m.fireSubscribers(event, anyList, opts, nil)
+		}
+	}
+	m.fireObservers(event, opts)
+	if m.dispatchTrace != nil {
+		m.callDispatchTrace(eventType, opts.order)
+	}
+	m.callHook("after fire", m.afterFire, eventType, event)
+	if len(m.metrics) != 0 {
+		d, name := m.since(start), m.typeName(eventType)
+		for _, r := range m.metrics {
+			r.EventFired(name, d)
+		}
+	}
+	if opts != nil && opts.isolated != nil {
+		opts.isolated.repanic()
+	}
+}
+
+// fireUnified fires a dereferenced copy of a pointer event to the subscribers of the value type,
+// or the address of a copy of a value event to the subscribers of the pointer type,
+// if pointer and value unification is enabled.
+func (m *manager) fireUnified(event Event, opts *fireOptions, wg *sync.WaitGroup) {
+	if !m.unifyPtrValue {
+		return
+	}
+	v := reflect.ValueOf(event)
+	switch {
+	case !v.IsValid():
+		return
+	case v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	default:
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	m.fireSubscribers(v.Interface(), m.list(v.Type()), opts, wg)
+}
+
+// maxEmbedDepth is the maximum depth of embedded struct fields matched by WithEmbeddedMatching.
+const maxEmbedDepth = 3
+
+// fireEmbedded fires the embedded struct fields of a struct or struct pointer event
+// to the subscribers of their types, if embedded matching is enabled.
+func (m *manager) fireEmbedded(event Event, opts *fireOptions, wg *sync.WaitGroup) {
+	if !m.matchEmbedded {
+		return
+	}
+	v := reflect.ValueOf(event)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	m.fireEmbeddedFields(v, opts, wg, 1)
+}
+
+func (m *manager) fireEmbeddedFields(v reflect.Value, opts *fireOptions, wg *sync.WaitGroup, depth int) {
+	if depth > maxEmbedDepth {
+		return
+	}
+	for _, f := range embeddedFields(v.Type()) {
+		fv := v.FieldByIndex(f.Index)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			m.fireSubscribers(fv.Interface(), m.list(fv.Type()), opts, wg)
+			fv = fv.Elem()
+		} else if fv.CanAddr() {
+			m.fireSubscribers(fv.Addr().Interface(), m.list(reflect.PointerTo(fv.Type())), opts, wg)
+		}
+		m.fireSubscribers(fv.Interface(), m.list(fv.Type()), opts, wg)
+		m.fireEmbeddedFields(fv, opts, wg, depth+1)
+	}
+}
+
+// embeddedFields returns the exported embedded fields of the struct or struct pointer type t
+// that are structs or struct pointers.
+func embeddedFields(t Type) []reflect.StructField {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous || !f.IsExported() {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// callHook runs a fire lifecycle hook if set.
+func (m *manager) callHook(name string, hook func(Type, Event), eventType Type, event Event) {
+	if hook == nil {
+		return
+	}
+	if m.recoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				m.log.Error(nil, "recovered from panic by a '"+name+"' hook",
+					"panic", r,
+					"eventType", m.typeName(eventType))
+			}
+		}()
+	}
+	hook(exportedType(eventType), event)
+}
+
+// fireSubscribers calls the subscribers of the list in order,
+// or concurrently in new goroutines added to wg if it is not nil.
+func (m *manager) fireSubscribers(event Event, list *subscriberList, opts *fireOptions, wg *sync.WaitGroup) {
 	if list == nil {
 		return
 	}
 	list.wg.Add(1)
 	defer list.wg.Done()
 
-	for _, sub := range list.subs {
-		m.callSubscriber(sub, event)
+	var subs []*subscriber
+	if opts != nil && opts.pinned == list {
+		subs = opts.pinnedSubs
+	} else {
+		subs = list.load()
+	}
+	stoppable, _ := event.(Stoppable)
+	for _, sub := range subs {
+		if stoppable != nil && stoppable.PropagationStopped() {
+			return
+		}
+		if !opts.match(sub) || m.deadlineExceeded(sub, event, opts) {
+			continue
+		}
+		if wg == nil {
+			m.callSubscriber(sub, event, opts)
+			if opts != nil && opts.afterEach != nil {
+				opts.afterEach(event)
+			}
+			continue
+		}
+		wg.Add(1)
+		list.wg.Add(1)
+		go func(sub *subscriber) {
+			defer wg.Done()
+			defer list.wg.Done()
+			m.callSubscriber(sub, event, opts)
+		}(sub)
 	}
 }
 
-func (m *manager) callSubscriber(sub *subscriber, event Event) {
-	if m.recoverPanic {
+// callSubscriber calls the subscriber with the event. The options may be nil.
+func (m *manager) callSubscriber(sub *subscriber, event Event, opts *fireOptions) {
+	if sub.expected != nil && !isType(event, sub.expected) {
+		m.log.WithValues(sub.logValues()...).Error(nil, "skipped event subscriber expecting another event type",
+			"eventType", m.typeName(m.typeOf(event)),
+			"eventGoType", fmt.Sprintf("%T", event),
+			"expectedGoType", sub.expected.String())
+		return
+	}
+	if opts != nil && opts.order != nil {
+		opts.order.add(sub.priority)
+	}
+	if m.trackHandlers {
+		defer m.track(sub, event)()
+	}
+	if m.onSlow != nil {
+		// Registered first to also measure subscribers up to a recovered panic
+		start := m.clock.Now()
+		defer func() {
+			if d := m.since(start); d > m.slowThreshold {
+				m.onSlow(HandlerInfo{
+					Type:     exportedType(m.typeOf(event)),
+					Priority: sub.priority,
+					Name:     sub.name,
+					Started:  start,
+				}, d)
+			}
+		}()
+	}
+	if (m.recoverPanic || (opts != nil && opts.onPanic != nil)) && (opts == nil || !opts.noRecover) {
 		defer func() {
 			if r := recover(); r != nil {
-				m.log.Error(nil, "recovered from panic from an event subscriber",
+				eventType := m.typeOf(event)
+				name := m.typeName(eventType)
+				m.log.WithValues(sub.logValues()...).Error(nil, "recovered from panic from an event subscriber",
 					"panic", r,
-					"eventType", typeOf(event),
-					"subscriberPriority", sub.priority)
+					"eventType", name)
+				for _, rec := range m.metrics {
+					rec.SubscriberPanicked(name, sub.name)
+				}
+				m.reportPanic(r, eventType, sub, opts)
 			}
 		}()
+	} else if opts != nil && opts.isolated != nil {
+		defer opts.isolated.catch()
+	}
+	if len(m.middleware) != 0 {
+		m.applyMiddleware(sub, opts)(event)
+		return
+	}
+	callHandler(sub, event, opts)
+}
+
+// callHandler calls the handler of the subscriber matching the kind of subscription.
+func callHandler(sub *subscriber, event Event, opts *fireOptions) {
+	if sub.metaFn != nil {
+		sub.metaFn(event, opts.metadata())
+		return
+	}
+	if sub.ctxFn != nil {
+		sub.ctxFn(opts.context(), event)
+		return
 	}
 	sub.fn(event)
 }
 
+// track adds a running subscriber call and returns the func to remove it when done.
+func (m *manager) track(sub *subscriber, event Event) (done func()) {
+	info := &HandlerInfo{
+		Type:     exportedType(m.typeOf(event)),
+		Priority: sub.priority,
+		Name:     sub.name,
+		Started:  m.clock.Now(),
+	}
+	m.runningMu.Lock()
+	m.running[info] = struct{}{}
+	m.runningMu.Unlock()
+	return func() {
+		m.runningMu.Lock()
+		delete(m.running, info)
+		m.runningMu.Unlock()
+	}
+}
+
+func (m *manager) StuckHandlers(olderThan time.Duration) []HandlerInfo {
+	m.runningMu.Lock()
+	defer m.runningMu.Unlock()
+	var stuck []HandlerInfo
+	for info := range m.running {
+		if m.since(info.Started) > olderThan {
+			stuck = append(stuck, *info)
+		}
+	}
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].Started.Before(stuck[j].Started)
+	})
+	return stuck
+}
+
+// isType reports whether the event is of the Go type t or implements it if t is an interface.
+func isType(event Event, t Type) bool {
+	et := reflect.TypeOf(event)
+	if et == nil {
+		return false
+	}
+	if t.Kind() == reflect.Interface {
+		return et.Implements(t)
+	}
+	return et == t
+}
+
+// typeOf returns the subscriber key of e, which is the result of the type key func if set
+// and otherwise the reflect.Type of e. Types are never passed to the type key func.
+// The nil type maps to wildcardKey.
+func (m *manager) typeOf(e Event) Type {
+	if m.typeKey != nil {
+		switch e.(type) {
+		case nil, reflect.Type, reflect.Value:
+		default:
+			if t := m.typeKey(e); t != nil {
+				return t
+			}
+		}
+	}
+	if t := typeOf(e); t != nil {
+		return t
+	}
+	return wildcardKey
+}
+
 // typeOf returns the reflect.Type of e.
 func typeOf(e Event) (t Type) {
 	switch o := e.(type) {
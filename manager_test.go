@@ -1,9 +1,18 @@
 package event
 
 import (
+	"context"
+	"errors"
+	"math"
 	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr/funcr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -56,3 +65,1699 @@ func TestPriorityAndCorrectType(t *testing.T) {
 	require.True(t, m.HasSubscriber(&myEvent{}))
 	require.Equal(t, calledAny, 1)
 }
+
+func TestBeforeAfterFire(t *testing.T) {
+	var calls []string
+	m := New(
+		WithBeforeFire(func(typ Type, e Event) {
+			require.Equal(t, typeOf(&myEvent{}), typ)
+			calls = append(calls, "before")
+			panic("recovered")
+		}),
+		WithAfterFire(func(typ Type, e Event) {
+			calls = append(calls, "after:"+e.(*myEvent).s)
+		}),
+	)
+
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"before", "after:"}, calls)
+
+	calls = nil
+	m.Subscribe(any(nil), 0, func(e Event) { calls = append(calls, "any") })
+	Subscribe(m, 0, func(e *myEvent) {
+		calls = append(calls, "typed")
+		e.s = "done"
+	})
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"before", "any", "typed", "after:done"}, calls)
+}
+
+func TestFireBatchUnique(t *testing.T) {
+	m := New()
+	var fired []string
+	Subscribe(m, 0, func(e *myEvent) { fired = append(fired, e.s) })
+	Subscribe(m, 0, func(e myEvent) { fired = append(fired, e.s) })
+
+	a, b := &myEvent{s: "a"}, &myEvent{s: "b"}
+	FireBatchUnique(m, a, b, a, myEvent{s: "v"}, myEvent{s: "v"}, b)
+	require.Equal(t, []string{"a", "b", "v", "v"}, fired)
+
+	fired = nil
+	FireBatchUniqueKey(m, func(e Event) any { return e.(*myEvent).s },
+		&myEvent{s: "x"}, &myEvent{s: "y"}, &myEvent{s: "x"})
+	require.Equal(t, []string{"x", "y"}, fired)
+}
+
+func TestInFlight(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	Subscribe(m, 0, func(e *myEvent) {
+		assert.Equal(t, 2, m.InFlight())
+		<-release
+	})
+	m.FireParallel(&myEvent{})
+	m.FireParallel(&myEvent{})
+	require.Eventually(t, func() bool { return m.InFlight() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	m.Wait()
+	require.Equal(t, 0, m.InFlight())
+}
+
+func TestSubscribeValue(t *testing.T) {
+	m := New()
+	var got reflect.Value
+	SubscribeValue(m, reflect.TypeOf(&myEvent{}), 0, func(v reflect.Value) { got = v })
+	m.Fire(&myEvent{s: "a"})
+	require.True(t, got.IsValid())
+	require.Equal(t, "a", got.Elem().Field(0).String())
+}
+
+func TestFireLazy(t *testing.T) {
+	m := New()
+	var built int
+	build := func() *myEvent {
+		built++
+		return &myEvent{s: "lazy"}
+	}
+	FireLazy(m, build)
+	require.Equal(t, 0, built)
+
+	var got string
+	Subscribe(m, 0, func(e *myEvent) { got = e.s })
+	FireLazy(m, build)
+	require.Equal(t, 1, built)
+	require.Equal(t, "lazy", got)
+}
+
+func TestUnsubscribeAllAndWait(t *testing.T) {
+	m := New()
+	started, release := make(chan struct{}), make(chan struct{})
+	var done bool
+	Subscribe(m, 0, func(e *myEvent) {
+		close(started)
+		<-release
+		done = true
+	})
+	m.FireParallel(&myEvent{})
+	<-started
+
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	n, err := m.UnsubscribeAllAndWait(&myEvent{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.True(t, done)
+	require.False(t, m.HasSubscriber(&myEvent{}))
+}
+
+func TestUnsubscribeAllAndWaitFromHandler(t *testing.T) {
+	m := New()
+	done := make(chan int)
+	var returned atomic.Bool
+	Subscribe(m, 0, func(*myEvent) {
+		// Waiting in a new goroutine, which returns once this handler has returned
+		go func() {
+			n, _ := m.UnsubscribeAllAndWait(&myEvent{})
+			require.True(t, returned.Load())
+			done <- n
+		}()
+		time.Sleep(10 * time.Millisecond)
+		returned.Store(true)
+	})
+	m.Fire(&myEvent{})
+	require.Equal(t, 1, <-done)
+	require.False(t, m.HasSubscriber())
+}
+
+func TestUnsubscribeAllAndWaitPanics(t *testing.T) {
+	m := New()
+	started, release := make(chan struct{}), make(chan struct{})
+	Subscribe(m, 1, func(e *myEvent) {
+		close(started)
+		<-release
+		panic("first")
+	})
+	Subscribe(m, 0, func(e *myEvent) { panic("second") })
+	Subscribe(m, 0, func(e *baseEvent) { panic("other") })
+	m.FireParallel(&myEvent{})
+	<-started
+	m.Fire(&baseEvent{}) // Not drained
+
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	n, err := m.UnsubscribeAllAndWait(&myEvent{})
+	require.Equal(t, 2, n)
+	require.EqualError(t, err, "recovered from panic by a handler of *event.myEvent with priority 1: first\n"+
+		"recovered from panic by a handler of *event.myEvent with priority 0: second")
+	var pe *PanicError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, "first", pe.Value)
+
+	n, err = m.UnsubscribeAllAndWait()
+	require.Equal(t, 1, n)
+	require.NoError(t, err)
+}
+
+func TestUnsubscribeAllAndWaitPanicAtRemoval(t *testing.T) {
+	m := New(WithLifecycleEvents(true))
+	started, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	Subscribe(m, 0, func(e *myEvent) {
+		close(started)
+		<-release
+		panic("at removal")
+	})
+	// Runs right after the lists are removed, before UnsubscribeAllAndWait waits
+	Subscribe(m, 0, func(e *SubscriberChanged) {
+		if e.Type == typeOf(&myEvent{}) && e.Count == 0 {
+			close(release)
+			<-done
+		}
+	})
+	m.FireParallel(&myEvent{}, func(Event) { close(done) })
+	<-started
+
+	n, err := m.UnsubscribeAllAndWait(&myEvent{})
+	require.Equal(t, 1, n)
+	require.EqualError(t, err, "recovered from panic by a handler of *event.myEvent with priority 0: at removal")
+}
+
+func TestRegisterType(t *testing.T) {
+	m := New().(*manager)
+	_, ok := m.TypeByName("my")
+	require.False(t, ok)
+	require.Equal(t, "*event.myEvent", m.typeName(typeOf(&myEvent{})))
+
+	m.RegisterType("my", &myEvent{})
+	typ, ok := m.TypeByName("my")
+	require.True(t, ok)
+	require.Equal(t, typeOf(&myEvent{}), typ)
+	require.Equal(t, "my", m.typeName(typ))
+
+	m.RegisterType("my", myEvent{})
+	typ, _ = m.TypeByName("my")
+	require.Equal(t, typeOf(myEvent{}), typ)
+	require.Equal(t, "*event.myEvent", m.typeName(typeOf(&myEvent{})))
+	require.Equal(t, "<nil>", m.typeName(nil))
+}
+
+type jsonEvent struct {
+	Name string `json:"name"`
+}
+
+func TestFireJSON(t *testing.T) {
+	m := New()
+	err := m.FireJSON("json", []byte(`{}`))
+	require.ErrorIs(t, err, ErrUnknownType)
+
+	var ptr *jsonEvent
+	var val jsonEvent
+	Subscribe(m, 0, func(e *jsonEvent) { ptr = e })
+	Subscribe(m, 0, func(e jsonEvent) { val = e })
+
+	m.RegisterType("json.ptr", &jsonEvent{})
+	m.RegisterType("json.val", jsonEvent{})
+	require.NoError(t, m.FireJSON("json.ptr", []byte(`{"name":"a"}`)))
+	require.NoError(t, m.FireJSON("json.val", []byte(`{"name":"b"}`)))
+	require.Equal(t, &jsonEvent{Name: "a"}, ptr)
+	require.Equal(t, jsonEvent{Name: "b"}, val)
+
+	require.Error(t, m.FireJSON("json.val", []byte(`{`)))
+}
+
+func TestFireParallelSync(t *testing.T) {
+	m := New()
+	Subscribe(m, 0, func(e *myEvent) { e.s += "a" })
+	Subscribe(m, 1, func(e *myEvent) { e.s += "b" })
+	require.Equal(t, "_ba", FireParallelSync(m, &myEvent{s: "_"}).s)
+
+	require.Equal(t, "_", FireParallelSync(Nop, &myEvent{s: "_"}).s)
+}
+
+func TestPriorityCleanup(t *testing.T) {
+	m := New()
+	var order []string
+	add := func(name string, priority int) {
+		Subscribe(m, priority, func(*myEvent) { order = append(order, name) })
+	}
+	cleanup := func(name string) {
+		SubscribeCleanup(m, func(*myEvent) { order = append(order, name) })
+	}
+	cleanup("cleanup1")
+	add("low", PriorityCleanup+1)
+	add("min", math.MinInt)
+	add("normal1", 0)
+	cleanup("cleanup2")
+	add("normal2", 0)
+	cleanup("cleanup3")
+
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"normal1", "normal2", "low", "min", "cleanup1", "cleanup2", "cleanup3"}, order)
+
+	// Dependencies and batches keep cleanup subscribers last
+	order = nil
+	m = New()
+	cleanup("cleanup")
+	SubscribeAfter(m, "after", math.MinInt, []string{"min"}, func(*myEvent) { order = append(order, "after") })
+	SubscribeNamed(m, "min", math.MinInt, func(*myEvent) { order = append(order, "min") })
+	SubscribeBatch(m, SubscribeSpec{EventType: &myEvent{}, Priority: math.MinInt, Fn: func(Event) { order = append(order, "batch") }})
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"min", "after", "batch", "cleanup"}, order)
+}
+
+func TestSubscribeAll(t *testing.T) {
+	m := New()
+	var calledAny int
+	unsubscribe := SubscribeAll(m, 10, func(e Event) {
+		calledAny++
+	})
+	require.True(t, m.HasSubscriber(&myEvent{}))
+
+	m.Fire(&myEvent{})
+	m.Fire(myEvent{})
+	m.Fire(1)
+	require.Equal(t, 3, calledAny)
+
+	unsubscribe()
+	m.Fire(&myEvent{})
+	require.Equal(t, 3, calledAny)
+}
+
+func TestDuplicateDetection(t *testing.T) {
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	m := New(WithDuplicateDetection(true), WithLogger(log))
+
+	handler := func(*myEvent) {}
+	Subscribe(m, 0, handler)
+	Subscribe(m, 0, func(*myEvent) {})
+	require.Empty(t, logs)
+
+	Subscribe(m, 1, handler)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "likely duplicate")
+}
+
+func TestFireRange(t *testing.T) {
+	m := New()
+	var order []int
+	for _, p := range []int{200, 100, 50, 0} {
+		p := p
+		Subscribe(m, p, func(*myEvent) { order = append(order, p) })
+	}
+	SubscribeAll(m, 150, func(Event) { order = append(order, -150) })
+
+	m.FireRange(&myEvent{}, 100, math.MaxInt)
+	require.Equal(t, []int{-150, 200, 100}, order)
+
+	order = nil
+	m.FireRange(&myEvent{}, math.MinInt, 99)
+	require.Equal(t, []int{50, 0}, order)
+}
+
+func TestFireConcurrent(t *testing.T) {
+	const n = 10
+	m := New()
+	var barrier sync.WaitGroup
+	barrier.Add(n)
+	var called atomic.Int32
+	for i := 0; i < n; i++ {
+		Subscribe(m, i, func(*myEvent) {
+			barrier.Done()
+			barrier.Wait() // Only returns if all subscribers run concurrently
+			called.Add(1)
+		})
+	}
+	Subscribe(m, 0, func(*myEvent) { panic("recovered") })
+
+	done := make(chan struct{})
+	go func() {
+		m.FireConcurrent(&myEvent{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("subscribers did not run concurrently")
+	}
+	require.EqualValues(t, n, called.Load())
+}
+
+func TestLogValues(t *testing.T) {
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	m := New(WithLogValues("service", "test"), WithLogger(log))
+
+	Subscribe(m, 0, func(*myEvent) { panic("recovered") })
+	m.Fire(&myEvent{})
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], `"service"="test"`)
+}
+
+func TestSlowHandlerThreshold(t *testing.T) {
+	var slow []int
+	m := New(WithSlowHandlerThreshold(5*time.Millisecond, func(typ Type, priority int, d time.Duration) {
+		require.Equal(t, typeOf(&myEvent{}), typ)
+		require.Greater(t, d, 5*time.Millisecond)
+		slow = append(slow, priority)
+	}))
+	Subscribe(m, 2, func(*myEvent) { time.Sleep(10 * time.Millisecond) })
+	Subscribe(m, 1, func(*myEvent) {})
+	Subscribe(m, 0, func(*myEvent) {
+		time.Sleep(10 * time.Millisecond)
+		panic("recovered")
+	})
+
+	m.Fire(&myEvent{})
+	require.Equal(t, []int{2, 0}, slow)
+}
+
+func TestSlowHandlerInfo(t *testing.T) {
+	var slow []HandlerInfo
+	m := New(WithSlowHandlerInfo(5*time.Millisecond, func(info HandlerInfo, d time.Duration) {
+		require.Greater(t, d, 5*time.Millisecond)
+		slow = append(slow, info)
+	}))
+	SubscribeNamed(m, "slow", 1, func(*myEvent) { time.Sleep(10 * time.Millisecond) })
+	SubscribeNamed(m, "fast", 0, func(*myEvent) {})
+
+	before := time.Now()
+	m.Fire(&myEvent{})
+	require.Len(t, slow, 1)
+	require.Equal(t, "slow", slow[0].Name)
+	require.Equal(t, 1, slow[0].Priority)
+	require.Equal(t, typeOf(&myEvent{}), slow[0].Type)
+	require.False(t, slow[0].Started.Before(before))
+}
+
+func TestStats(t *testing.T) {
+	m := New()
+	fired, last := m.Stats(&myEvent{})
+	require.Zero(t, fired)
+	require.True(t, last.IsZero())
+
+	before := time.Now()
+	m.Fire(&myEvent{})
+	m.Fire(&myEvent{})
+	m.Fire(myEvent{})
+
+	fired, last = m.Stats(&myEvent{})
+	require.EqualValues(t, 2, fired)
+	require.False(t, last.Before(before))
+	fired, _ = m.Stats(reflect.TypeOf(myEvent{}))
+	require.EqualValues(t, 1, fired)
+
+	fired, last = Nop.Stats(&myEvent{})
+	require.Zero(t, fired)
+	require.True(t, last.IsZero())
+}
+
+func TestPtrValueUnification(t *testing.T) {
+	m := New(WithPtrValueUnification(true))
+	var order []string
+	Subscribe(m, 0, func(e myEvent) {
+		order = append(order, "value:"+e.s)
+		e.s += "!" // Mutates a copy
+	})
+	Subscribe(m, 0, func(e *myEvent) {
+		order = append(order, "ptr:"+e.s)
+		e.s += "p"
+	})
+
+	e := &myEvent{s: "a"}
+	m.Fire(e)
+	require.Equal(t, []string{"ptr:a", "value:ap"}, order)
+	require.Equal(t, "ap", e.s)
+
+	order = nil
+	v := myEvent{s: "b"}
+	m.Fire(v)
+	require.Equal(t, []string{"value:b", "ptr:b"}, order)
+	require.Equal(t, "b", v.s)
+
+	// A nil pointer can not be dereferenced for value subscribers
+	order = nil
+	require.NotPanics(t, func() { m.Fire((*myEvent)(nil)) })
+	require.Empty(t, order)
+}
+
+func TestReset(t *testing.T) {
+	var before int
+	m := New(WithBeforeFire(func(Type, Event) { before++ }))
+	m.RegisterType("my", &myEvent{})
+	Subscribe(m, 0, func(*myEvent) {})
+	m.Fire(&myEvent{})
+
+	m.Reset()
+	require.False(t, m.HasSubscriber())
+	fired, _ := m.Stats(&myEvent{})
+	require.Zero(t, fired)
+	_, ok := m.TypeByName("my")
+	require.True(t, ok)
+
+	m.Fire(&myEvent{})
+	require.Equal(t, 2, before)
+}
+
+func TestResetWait(t *testing.T) {
+	m := New()
+	started, release := make(chan struct{}), make(chan struct{})
+	var done atomic.Bool
+	Subscribe(m, 0, func(*myEvent) {
+		close(started)
+		<-release
+		done.Store(true)
+	})
+	m.FireParallel(&myEvent{})
+	<-started
+
+	// Waiting for the type covers the fire started before the reset
+	m.Reset()
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	m.Wait(&myEvent{})
+	require.True(t, done.Load())
+	fired, _ := m.Stats(&myEvent{})
+	require.Zero(t, fired)
+}
+
+func BenchmarkSubscribe(b *testing.B) {
+	m := New()
+	for i := 0; i < 5000; i++ {
+		Subscribe(m, i%100, func(*myEvent) {})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Subscribe(m, i%100, func(*myEvent) {})()
+	}
+}
+
+func BenchmarkSubscribeTypes(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		types := make([]reflect.Type, n)
+		for i := range types {
+			types[i] = reflect.ArrayOf(i, reflect.TypeOf(byte(0)))
+		}
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m := New()
+				for _, t := range types {
+					m.Subscribe(t, 0, func(Event) {})
+				}
+			}
+		})
+	}
+}
+
+func TestExpectedTypes(t *testing.T) {
+	types := make([]reflect.Type, 1000)
+	for i := range types {
+		types[i] = reflect.ArrayOf(i, reflect.TypeOf(byte(0)))
+	}
+	fireAll := func(opts ...ManagerOption) float64 {
+		return testing.AllocsPerRun(5, func() {
+			m := New(opts...)
+			for _, typ := range types {
+				m.Fire(reflect.New(typ).Elem().Interface())
+			}
+		})
+	}
+	// Pre-sizing avoids growing the map of fire stats
+	require.Less(t, fireAll(WithExpectedTypes(len(types))), fireAll())
+}
+
+func BenchmarkExpectedTypes(b *testing.B) {
+	types := make([]reflect.Type, 1000)
+	for i := range types {
+		types[i] = reflect.ArrayOf(i, reflect.TypeOf(byte(0)))
+	}
+	events := make([]Event, len(types))
+	for i, typ := range types {
+		events[i] = reflect.New(typ).Elem().Interface()
+	}
+	for _, n := range []int{0, len(types)} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				m := New(WithExpectedTypes(n))
+				for _, e := range events {
+					m.Fire(e)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHasSubscriber(b *testing.B) {
+	m := New()
+	Subscribe(m, 0, func(*myEvent) {})
+	e, t := &myEvent{}, KeyOf[*myEvent]()
+	b.Run("variadic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.HasSubscriber(e)
+		}
+	})
+	b.Run("type", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.HasSubscriberType(t)
+		}
+	})
+}
+
+func TestHasSubscriberType(t *testing.T) {
+	m := New()
+	require.False(t, m.HasSubscriberType(KeyOf[*myEvent]()))
+	require.False(t, m.HasSubscriberType(nil))
+	Subscribe(m, 0, func(*myEvent) {})
+	require.True(t, m.HasSubscriberType(KeyOf[*myEvent]()))
+	require.False(t, m.HasSubscriberType(KeyOf[myEvent]()))
+	require.False(t, m.HasSubscriberType(nil))
+	SubscribeAll(m, 0, func(Event) {})
+	require.True(t, m.HasSubscriberType(KeyOf[myEvent]()))
+	require.True(t, m.HasSubscriberType(nil))
+}
+
+func TestHasSubscriberPtrValueUnification(t *testing.T) {
+	for _, unify := range []bool{false, true} {
+		m := New(WithPtrValueUnification(unify))
+		Subscribe(m, 0, func(myEvent) {})
+		require.True(t, m.HasSubscriber(myEvent{}))
+		require.Equal(t, unify, m.HasSubscriber(&myEvent{}))
+
+		var built bool
+		FireLazy(m, func() *myEvent {
+			built = true
+			return &myEvent{}
+		})
+		require.Equal(t, unify, built)
+
+		m = New(WithPtrValueUnification(unify))
+		Subscribe(m, 0, func(*myEvent) {})
+		require.True(t, m.HasSubscriber(&myEvent{}))
+		require.Equal(t, unify, m.HasSubscriber(myEvent{}))
+		require.False(t, m.HasSubscriber(&jsonEvent{}))
+	}
+}
+
+type namedEvent interface{ EventName() string }
+
+type createdV1 struct{ name string }
+type createdV2 struct{ name string }
+
+func (e *createdV1) EventName() string { return e.name }
+func (e *createdV2) EventName() string { return e.name }
+
+func TestTypeKeyFunc(t *testing.T) {
+	var m Manager
+	m = New(WithTypeKeyFunc(func(e Event) Type {
+		if n, ok := e.(namedEvent); ok {
+			typ, _ := m.TypeByName(n.EventName())
+			return typ
+		}
+		return nil
+	}))
+	m.RegisterType("created", reflect.TypeOf(struct{ created bool }{}))
+
+	var got []Event
+	unsubscribe := m.Subscribe(&createdV1{name: "created"}, 0, func(e Event) { got = append(got, e) })
+	require.True(t, m.HasSubscriber(&createdV2{name: "created"}))
+
+	m.Fire(&createdV1{name: "created"})
+	m.Fire(&createdV2{name: "created"})
+	m.Fire(&createdV2{name: "other"})
+	m.Fire(&myEvent{})
+	require.Equal(t, []Event{&createdV1{name: "created"}, &createdV2{name: "created"}}, got)
+
+	unsubscribe()
+	require.False(t, m.HasSubscriber(&createdV1{name: "created"}))
+}
+
+func TestLifecycleEvents(t *testing.T) {
+	m := New(WithLifecycleEvents(true))
+	var changes []SubscriberChanged
+	unsubscribeLifecycle := Subscribe(m, 0, func(e *SubscriberChanged) { changes = append(changes, *e) })
+	require.Empty(t, changes)
+
+	typ := typeOf(&myEvent{})
+	unsubscribe := Subscribe(m, 0, func(*myEvent) {})
+	Subscribe(m, 0, func(*myEvent) {})
+	unsubscribe()
+	unsubscribe()
+	m.UnsubscribeAll(&myEvent{})
+	require.Equal(t, []SubscriberChanged{
+		{Type: typ, Count: 1},
+		{Type: typ, Count: 2},
+		{Type: typ, Count: 1},
+		{Type: typ, Count: 0},
+	}, changes)
+
+	unsubscribeLifecycle()
+	Subscribe(m, 0, func(*myEvent) {})
+	require.Len(t, changes, 4)
+}
+
+func TestInflightLimit(t *testing.T) {
+	m := New(WithInflightLimit(typeOf(&myEvent{}), 2))
+	var running, maxRunning atomic.Int32
+	Subscribe(m, 0, func(*myEvent) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			max := maxRunning.Load()
+			if n <= max || maxRunning.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	})
+	for i := 0; i < 10; i++ {
+		m.FireParallel(&myEvent{})
+	}
+	require.Eventually(t, func() bool { return m.QueueDepth() > 0 }, time.Second, time.Millisecond)
+	m.Wait()
+	require.EqualValues(t, 2, maxRunning.Load())
+	require.Zero(t, m.QueueDepth())
+}
+
+func TestInflightLimitInvalid(t *testing.T) {
+	require.PanicsWithValue(t, "event: WithInflightLimit requires a positive limit, got 0", func() {
+		WithInflightLimit(KeyOf[*myEvent](), 0)
+	})
+	require.Panics(t, func() { New(WithInflightLimit(KeyOf[*myEvent](), -1)) })
+}
+
+func TestUnsubscribeFunc(t *testing.T) {
+	m := New()
+	var called int
+	handler := func(*myEvent) { called++ }
+	require.False(t, Unsubscribe(m, handler))
+
+	Subscribe(m, 0, handler)
+	Subscribe(m, 0, func(*myEvent) { called += 10 })
+	require.True(t, Unsubscribe(m, handler))
+	require.False(t, Unsubscribe(m, handler))
+
+	m.Fire(&myEvent{})
+	require.Equal(t, 10, called)
+	require.False(t, Unsubscribe(Nop, handler))
+}
+
+func TestWaitFireParallelAfter(t *testing.T) {
+	m := New()
+	Subscribe(m, 0, func(*myEvent) {})
+
+	var afterDone atomic.Bool
+	m.FireParallel(&myEvent{}, func(Event) {
+		time.Sleep(10 * time.Millisecond)
+		afterDone.Store(true)
+	})
+	m.Wait(&myEvent{})
+	require.True(t, afterDone.Load())
+
+	// Also covered without subscribers
+	afterDone.Store(false)
+	m.FireParallel(&jsonEvent{}, func(Event) {
+		time.Sleep(10 * time.Millisecond)
+		afterDone.Store(true)
+	})
+	m.Wait(&jsonEvent{})
+	require.True(t, afterDone.Load())
+}
+
+func TestDrain(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	Subscribe(m, 0, func(*myEvent) { <-release })
+	m.FireParallel(&myEvent{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := m.Drain(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, err.Error(), "1 fires")
+
+	close(release)
+	require.NoError(t, m.Drain(context.Background()))
+}
+
+func TestDrainTimeoutNoGoroutine(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	Subscribe(m, 0, func(*myEvent) { <-release })
+	m.FireParallel(&myEvent{})
+
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 100; i++ {
+		require.ErrorIs(t, m.Drain(ctx), context.Canceled)
+	}
+	require.Equal(t, before, runtime.NumGoroutine())
+
+	done := make(chan error)
+	go func() { done <- m.Drain(context.Background()) }()
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestBridgeChan(t *testing.T) {
+	m := New()
+	var fired []string
+	Subscribe(m, 0, func(e *myEvent) { fired = append(fired, e.s) })
+
+	ch := make(chan *myEvent)
+	done := BridgeChan(context.Background(), m, ch)
+	ch <- &myEvent{s: "a"}
+	ch <- &myEvent{s: "b"}
+	close(ch)
+	<-done
+	require.Equal(t, []string{"a", "b"}, fired)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done = BridgeChan(ctx, m, make(chan *myEvent))
+	cancel()
+	<-done
+}
+
+func TestSubscribeMismatchedType(t *testing.T) {
+	// Both event types share the key of *myEvent
+	m := New(WithTypeKeyFunc(func(Event) Type { return typeOf(&myEvent{}) }), WithRecoverPanic(false))
+	var called int
+	Subscribe(m, 0, func(*myEvent) { called++ })
+
+	require.NotPanics(t, func() { m.Fire(&jsonEvent{}) })
+	require.Equal(t, 0, called)
+	m.Fire(&myEvent{})
+	require.Equal(t, 1, called)
+}
+
+func TestFireParallelErr(t *testing.T) {
+	m := New()
+	require.NoError(t, <-FireParallelErr(m, &myEvent{}, func(*myEvent) error { return nil }))
+
+	errTest := errors.New("test")
+	var calls int
+	errs := FireParallelErr(m, &myEvent{},
+		func(*myEvent) error { calls++; return errTest },
+		func(*myEvent) error { calls++; return nil },
+	)
+	require.ErrorIs(t, <-errs, errTest)
+	_, ok := <-errs
+	require.False(t, ok)
+	require.Equal(t, 1, calls)
+
+	err := <-FireParallelErr(m, &myEvent{}, func(*myEvent) error { panic("boom") })
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestPublisherSubscriber(t *testing.T) {
+	var _ Publisher = Nop
+	var _ Subscriber = Nop
+
+	m := New()
+	var sub Subscriber = m
+	var pub Publisher = m
+
+	var got string
+	Subscribe(sub, 0, func(e *myEvent) { got = e.s })
+	require.True(t, m.HasSubscriber(&myEvent{}))
+	pub.Fire(&myEvent{s: "a"})
+	require.Equal(t, "a", got)
+	require.Equal(t, "a", FireParallelSync(pub, &myEvent{s: "a"}).s)
+	require.Equal(t, 1, sub.UnsubscribeAll())
+}
+
+func TestFireParallelConcurrent(t *testing.T) {
+	const n = 5
+	m := New()
+	var barrier sync.WaitGroup
+	barrier.Add(n)
+	var called atomic.Int32
+	for i := 0; i < n; i++ {
+		Subscribe(m, i, func(*myEvent) {
+			barrier.Done()
+			barrier.Wait() // Only returns if all subscribers run concurrently
+			called.Add(1)
+		})
+	}
+
+	var afterCalled int32
+	m.FireParallelConcurrent(&myEvent{}, func(Event) { afterCalled = called.Load() })
+	m.Wait()
+	require.EqualValues(t, n, afterCalled)
+}
+
+func TestStuckHandlers(t *testing.T) {
+	m := New(WithHandlerTracking(true))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	Subscribe(m, 7, func(*myEvent) {
+		close(started)
+		<-release
+	})
+	Subscribe(m, 0, func(*myEvent) {})
+
+	require.Empty(t, m.StuckHandlers(0))
+	m.FireParallel(&myEvent{})
+	<-started
+
+	require.Empty(t, m.StuckHandlers(time.Hour))
+	time.Sleep(10 * time.Millisecond)
+	stuck := m.StuckHandlers(time.Millisecond)
+	require.Len(t, stuck, 1)
+	require.Equal(t, typeOf(&myEvent{}), stuck[0].Type)
+	require.Equal(t, 7, stuck[0].Priority)
+
+	close(release)
+	m.Wait()
+	require.Empty(t, m.StuckHandlers(0))
+
+	require.Empty(t, New().StuckHandlers(0)) // Not tracked
+}
+
+func TestSubscribeNamed(t *testing.T) {
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	m := New(WithLogger(log), WithHandlerTracking(true))
+
+	var stuck []HandlerInfo
+	SubscribeNamed(m, "audit", 1, func(*myEvent) { stuck = m.StuckHandlers(-1) })
+	SubscribeNamed(m, "broken", 0, func(*myEvent) { panic("boom") })
+	Subscribe(m, -1, func(*myEvent) { panic("boom") })
+	m.Fire(&myEvent{})
+
+	require.Len(t, stuck, 1)
+	require.Equal(t, "audit", stuck[0].Name)
+	require.Len(t, logs, 2)
+	require.Contains(t, logs[0], `"subscriberName"="broken"`)
+	require.NotContains(t, logs[1], "subscriberName")
+	require.Contains(t, logs[1], `"subscriberPriority"=-1`)
+}
+
+type baseEvent struct{ ID int }
+
+type EmbeddedAudit struct{ User string }
+
+type userCreated struct {
+	baseEvent // Unexported, never matched
+	*EmbeddedAudit
+	EmbeddedBase
+	Name string
+}
+
+type EmbeddedBase struct{ baseEvent }
+
+type EmbeddedNested struct{ EmbeddedBase }
+
+func TestEmbeddedMatching(t *testing.T) {
+	m := New(WithEmbeddedMatching(true))
+	var got []Event
+	record := func(e Event) { got = append(got, e) }
+	Subscribe(m, 0, func(e EmbeddedBase) { record(e) })
+	Subscribe(m, 0, func(e *EmbeddedBase) {
+		record(e)
+		e.baseEvent.ID = 2
+	})
+	Subscribe(m, 0, func(e *EmbeddedAudit) { record(e) })
+	Subscribe(m, 0, func(e baseEvent) { record(e) })
+	require.True(t, m.HasSubscriber(&userCreated{}))
+
+	audit := &EmbeddedAudit{User: "admin"}
+	e := &userCreated{EmbeddedAudit: audit, EmbeddedBase: EmbeddedBase{baseEvent{ID: 1}}}
+	m.Fire(e)
+	require.Equal(t, []Event{audit, &e.EmbeddedBase, EmbeddedBase{baseEvent{ID: 2}}}, got)
+	require.Equal(t, 2, e.EmbeddedBase.ID)
+
+	got = nil
+	m.Fire(userCreated{EmbeddedBase: EmbeddedBase{baseEvent{ID: 1}}}) // Not addressable, nil pointer skipped
+	require.Equal(t, []Event{EmbeddedBase{baseEvent{ID: 1}}}, got)
+
+	got = nil
+	m.Fire(EmbeddedNested{EmbeddedBase{baseEvent{ID: 3}}})
+	require.Equal(t, []Event{EmbeddedBase{baseEvent{ID: 3}}}, got)
+
+	got = nil
+	New().Fire(e)
+	require.Empty(t, got)
+	require.False(t, New().HasSubscriber(&userCreated{}))
+}
+
+func TestEmbeddedMatchingHasSubscriber(t *testing.T) {
+	m := New(WithEmbeddedMatching(true))
+	var called int
+	Subscribe(m, 0, func(*EmbeddedBase) { called++ })
+
+	// *EmbeddedBase subscribers are only called for addressable events
+	require.False(t, m.HasSubscriber(userCreated{}))
+	require.False(t, m.HasSubscriber(EmbeddedNested{}))
+	m.Fire(userCreated{})
+	m.Fire(EmbeddedNested{})
+	require.Zero(t, called)
+
+	require.True(t, m.HasSubscriber(&userCreated{}))
+	require.True(t, m.HasSubscriber(&EmbeddedNested{}))
+	m.Fire(&EmbeddedNested{})
+	require.Equal(t, 1, called)
+
+	// Embedded pointers are addressable
+	m = New(WithEmbeddedMatching(true))
+	Subscribe(m, 0, func(EmbeddedAudit) {})
+	require.True(t, m.HasSubscriber(userCreated{}))
+}
+
+func TestShutdownHook(t *testing.T) {
+	var order []string
+	release := make(chan struct{})
+	m := New(
+		WithShutdownHook(func() { order = append(order, "first") }),
+		WithShutdownHook(func() { panic("boom") }),
+		WithShutdownHook(func() { order = append(order, "last") }),
+	)
+	Subscribe(m, 0, func(*myEvent) {
+		<-release
+		order = append(order, "handler")
+	})
+	m.FireParallel(&myEvent{})
+	go close(release)
+
+	require.NoError(t, m.Close())
+	require.Equal(t, []string{"handler", "last", "first"}, order)
+	require.NoError(t, m.Close())
+	require.Len(t, order, 3)
+}
+
+func TestShutdownHookPanicWithoutRecovery(t *testing.T) {
+	var ran bool
+	m := New(
+		WithRecoverPanic(false),
+		WithShutdownHook(func() { ran = true }),
+		WithShutdownHook(func() { panic("boom") }),
+	)
+	require.NotPanics(t, func() { require.NoError(t, m.Close()) })
+	require.True(t, ran)
+}
+
+func TestScheduler(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		tasks []func()
+	)
+	m := New(WithScheduler(func(task func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		tasks = append(tasks, task)
+	}))
+	var called, afterCalled int
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	m.FireParallel(&myEvent{}, func(Event) { afterCalled++ })
+	require.Equal(t, 1, m.InFlight())
+	require.Zero(t, called)
+
+	waited := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("Wait returned before the scheduler ran the task")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	mu.Lock()
+	require.Len(t, tasks, 1)
+	require.Equal(t, 1, m.QueueDepth())
+	tasks[0]()
+	mu.Unlock()
+	<-waited
+	require.Equal(t, 1, called)
+	require.Equal(t, 1, afterCalled)
+	require.Zero(t, m.InFlight())
+	require.Zero(t, m.QueueDepth())
+}
+
+func TestFireNil(t *testing.T) {
+	var hooked []Type
+	m := New(WithBeforeFire(func(t Type, _ Event) { hooked = append(hooked, t) }))
+	var wildcard, typed int
+	var got *myEvent
+	SubscribeAll(m, 0, func(Event) { wildcard++ })
+	Subscribe(m, 0, func(e *myEvent) {
+		typed++
+		got = e
+	})
+
+	require.NotPanics(t, func() { m.Fire(nil) })
+	require.Equal(t, 1, wildcard)
+	require.Zero(t, typed)
+
+	got = &myEvent{}
+	require.NotPanics(t, func() { m.Fire((*myEvent)(nil)) })
+	require.Equal(t, 2, wildcard)
+	require.Equal(t, 1, typed)
+	require.Nil(t, got)
+
+	require.Equal(t, []Type{nil, typeOf(&myEvent{})}, hooked)
+	fired, _ := m.Stats(nil)
+	require.EqualValues(t, 1, fired)
+	require.True(t, m.HasSubscriber(nil))
+	require.Equal(t, 1, m.UnsubscribeAll(nil))
+	require.False(t, m.HasSubscriber(nil))
+}
+
+func TestFireNilPaths(t *testing.T) {
+	m := New(WithPtrValueUnification(true), WithEmbeddedMatching(true))
+	var wildcard, ptr, value atomic.Int32
+	SubscribeAll(m, 0, func(Event) { wildcard.Add(1) })
+	Subscribe(m, 0, func(*myEvent) { ptr.Add(1) })
+	Subscribe(m, 0, func(myEvent) { value.Add(1) })
+
+	fires := map[string]func(Event){
+		"Fire":           m.Fire,
+		"FireConcurrent": m.FireConcurrent,
+		"FireCtx":        func(e Event) { m.FireCtx(context.Background(), e) },
+		"FireRange":      func(e Event) { m.FireRange(e, math.MinInt, math.MaxInt) },
+		"FireParallel": func(e Event) {
+			m.FireParallel(e)
+			m.Wait()
+		},
+		"FireLazy": func(e Event) { m.FireLazy(e, func() Event { return e }) },
+	}
+	for name, fire := range fires {
+		t.Run(name, func(t *testing.T) {
+			wildcard.Store(0)
+			ptr.Store(0)
+			value.Store(0)
+
+			require.NotPanics(t, func() { fire(nil) })
+			require.EqualValues(t, 1, wildcard.Load())
+			require.Zero(t, ptr.Load())
+
+			require.NotPanics(t, func() { fire((*myEvent)(nil)) })
+			require.EqualValues(t, 2, wildcard.Load())
+			require.EqualValues(t, 1, ptr.Load())
+			require.Zero(t, value.Load())
+		})
+	}
+}
+
+func TestWildcardDisabled(t *testing.T) {
+	m := New(WithWildcardDisabled())
+	require.Panics(t, func() { SubscribeAll(m, 0, func(Event) {}) })
+	require.Panics(t, func() { m.Subscribe(nil, 0, func(Event) {}) })
+	require.False(t, m.HasSubscriber())
+
+	var called int
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	m.Fire(&myEvent{})
+	require.NotPanics(t, func() { m.Fire(nil) })
+	require.Equal(t, 1, called)
+}
+
+func TestMerge(t *testing.T) {
+	m := New()
+	var called []int
+	unsubscribe := Merge(
+		Subscribe(m, 0, func(*myEvent) {}),
+		func() { called = append(called, 1); panic("first") },
+		func() { called = append(called, 2); panic("second") },
+		func() { called = append(called, 3) },
+	)
+	require.True(t, m.HasSubscriber(&myEvent{}))
+	require.PanicsWithValue(t, "first", unsubscribe)
+	require.Equal(t, []int{1, 2, 3}, called)
+	require.False(t, m.HasSubscriber(&myEvent{}))
+
+	require.NotPanics(t, Merge())
+}
+
+func TestFireReduce(t *testing.T) {
+	m := New()
+	Subscribe(m, 2, func(e *myEvent) { e.s = "a" })
+	Subscribe(m, 1, func(e *myEvent) { e.s = "b" })
+	Subscribe(m, 0, func(e *myEvent) { panic("boom") })
+	SubscribeAll(m, -1, func(e Event) { e.(*myEvent).s += "!" })
+
+	got := FireReduce(m, &myEvent{}, []string{}, func(acc []string, e *myEvent) []string {
+		return append(acc, e.s)
+	})
+	require.Equal(t, []string{"!", "a", "b", "b"}, got)
+
+	count := FireReduce(Nop, &myEvent{}, 0, func(acc int, _ *myEvent) int { return acc + 1 })
+	require.Equal(t, 1, count)
+}
+
+func TestConcurrentHarness(t *testing.T) {
+	const (
+		workers    = 8
+		iterations = 2000
+	)
+	m := New()
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		w := w
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				switch (w + i) % 6 {
+				case 0, 1:
+					unsubscribe := Subscribe(m, i%3, func(*myEvent) {})
+					if i%2 == 0 {
+						unsubscribe()
+					}
+				case 2:
+					m.Fire(&myEvent{})
+				case 3:
+					m.FireParallel(&myEvent{})
+				case 4:
+					SubscribeAll(m, i%3, func(Event) {})()
+				case 5:
+					if i%50 == 0 {
+						m.UnsubscribeAll()
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	m.Wait()
+}
+
+func TestKeyOf(t *testing.T) {
+	require.Equal(t, typeOf(&myEvent{}), KeyOf[*myEvent]())
+	require.Equal(t, typeOf(myEvent{}), KeyOf[myEvent]())
+	require.Nil(t, KeyOf[any]())
+	require.Nil(t, KeyOf[namedEvent]())
+
+	m := New(WithInflightLimit(KeyOf[*myEvent](), 1))
+	var called int
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	m.Fire(&myEvent{})
+	require.Equal(t, 1, called)
+}
+
+func TestFireParallelChanCtx(t *testing.T) {
+	m := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var called []int
+	Subscribe(m, 1, func(e *myEvent) {
+		called = append(called, 1)
+		e.s = "done"
+	})
+	Subscribe(m, 0, func(e *myEvent) {
+		called = append(called, 0)
+	})
+
+	e, ok := <-FireParallelChanCtx(ctx, m, &myEvent{})
+	require.True(t, ok)
+	require.Equal(t, "done", e.s)
+	require.Equal(t, []int{1, 0}, called)
+
+	called = nil
+	Subscribe(m, 2, func(*myEvent) { cancel() })
+	_, ok = <-FireParallelChanCtx(ctx, m, &myEvent{})
+	require.False(t, ok)
+	require.Empty(t, called)
+}
+
+// replacingPublisher is a Manager passing a replacement event to the after handlers of FireParallel.
+type replacingPublisher struct {
+	Manager
+	replacement Event
+}
+
+func (p *replacingPublisher) FireParallel(event Event, after ...HandlerFunc) {
+	p.Manager.FireParallel(event, func(Event) {
+		for _, fn := range after {
+			fn(p.replacement)
+		}
+	})
+}
+
+func TestFireParallelChanCtxDispatchedEvent(t *testing.T) {
+	replacement := &myEvent{s: "replaced"}
+	p := &replacingPublisher{Manager: New(), replacement: replacement}
+	e, ok := <-FireParallelChanCtx(context.Background(), p, &myEvent{})
+	require.True(t, ok)
+	require.Same(t, replacement, e)
+	require.Same(t, replacement, <-FireParallelChan[*myEvent](p, &myEvent{}))
+}
+
+func TestStrictTypes(t *testing.T) {
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	m := New(WithStrictTypes(true), WithLogger(log), WithTypeKeyFunc(func(e Event) Type {
+		if _, ok := e.(jsonEvent); ok {
+			return typeOf(&myEvent{}) // Shares the key with *myEvent
+		}
+		return nil
+	}))
+	var called int
+	m.Subscribe(&myEvent{}, 0, func(e Event) {
+		_ = e.(*myEvent)
+		called++
+	})
+	m.Fire(&myEvent{})
+	require.Equal(t, 1, called)
+	require.Empty(t, logs)
+
+	m.Fire(jsonEvent{})
+	require.Equal(t, 1, called)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], `"expectedGoType"="*event.myEvent"`)
+	require.Contains(t, logs[0], `"eventGoType"="event.jsonEvent"`)
+}
+
+func TestFireNoWildcard(t *testing.T) {
+	m := New()
+	var typed, wildcard int
+	Subscribe(m, 0, func(*myEvent) { typed++ })
+	SubscribeAll(m, 0, func(Event) { wildcard++ })
+
+	m.FireNoWildcard(&myEvent{})
+	m.FireNoWildcard(nil)
+	require.Equal(t, 1, typed)
+	require.Zero(t, wildcard)
+
+	m.Fire(&myEvent{})
+	require.Equal(t, 2, typed)
+	require.Equal(t, 1, wildcard)
+}
+
+func TestSubscribeRetry(t *testing.T) {
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	m := New(WithLogger(log))
+	var attempts int
+	SubscribeRetry(m, 0, 2, time.Millisecond, func(e *myEvent) error {
+		attempts++
+		if e.s == "flaky" && attempts < 3 {
+			return errors.New("unavailable")
+		}
+		if e.s == "broken" {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	m.Fire(&myEvent{s: "flaky"})
+	require.Equal(t, 3, attempts)
+	require.Empty(t, logs)
+
+	attempts = 0
+	m.Fire(&myEvent{s: "broken"})
+	require.Equal(t, 3, attempts)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "failed after retries")
+}
+
+func TestIsNop(t *testing.T) {
+	require.True(t, IsNop(Nop))
+	require.False(t, IsNop(New()))
+}
+
+func TestFireTagged(t *testing.T) {
+	m := New()
+	var called []string
+	SubscribeTagged(m, 2, []string{"tenant:a"}, func(*myEvent) { called = append(called, "a") })
+	SubscribeTagged(m, 1, []string{"tenant:b", "beta"}, func(*myEvent) { called = append(called, "b") })
+	Subscribe(m, 0, func(*myEvent) { called = append(called, "untagged") })
+	SubscribeAll(m, 0, func(Event) { called = append(called, "wildcard") })
+
+	has := func(tag string) func([]string) bool {
+		return func(tags []string) bool {
+			for _, t := range tags {
+				if t == tag {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	m.FireTagged(&myEvent{}, has("beta"))
+	require.Equal(t, []string{"b"}, called)
+
+	called = nil
+	m.FireTagged(&myEvent{}, func(tags []string) bool { return len(tags) == 0 || has("tenant:a")(tags) })
+	require.Equal(t, []string{"wildcard", "a", "untagged"}, called)
+
+	called = nil
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"wildcard", "a", "b", "untagged"}, called)
+}
+
+func TestFireNoRecover(t *testing.T) {
+	m := New()
+	var called int
+	Subscribe(m, 1, func(*myEvent) { panic("boom") })
+	Subscribe(m, 0, func(*myEvent) { called++ })
+
+	require.PanicsWithValue(t, "boom", func() { m.FireNoRecover(&myEvent{}) })
+	require.Zero(t, called)
+	require.Zero(t, m.InFlight())
+	m.Wait(&myEvent{}) // Does not block
+
+	m.Fire(&myEvent{})
+	require.Equal(t, 1, called)
+}
+
+func TestWildcardLast(t *testing.T) {
+	for _, last := range []bool{false, true} {
+		m := New(WithWildcardLast(last))
+		var seen string
+		Subscribe(m, 0, func(e *myEvent) { e.s = "mutated" })
+		SubscribeAll(m, math.MaxInt, func(e Event) { seen = e.(*myEvent).s })
+		m.Fire(&myEvent{s: "original"})
+		if last {
+			require.Equal(t, "mutated", seen)
+		} else {
+			require.Equal(t, "original", seen)
+		}
+	}
+}
+
+func TestWaitDuringUnsubscribeAll(t *testing.T) {
+	m := New()
+	var active atomic.Int32
+	handler := func(*myEvent) {
+		active.Add(1)
+		defer active.Add(-1)
+		time.Sleep(time.Millisecond)
+	}
+	Subscribe(m, 0, handler)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				m.UnsubscribeAll()
+				Subscribe(m, 0, handler)
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		m.FireParallel(&myEvent{})
+		m.Wait(&myEvent{})
+		require.Zero(t, active.Load(), "Wait returned with a handler running")
+	}
+	close(stop)
+	<-done
+}
+
+func TestFireParallelHandle(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	Subscribe(m, 0, func(e *myEvent) {
+		if e.s == "slow" {
+			<-release
+		}
+	})
+
+	var afterCalled atomic.Bool
+	h := m.FireParallelHandle(&myEvent{}, func(Event) { afterCalled.Store(true) })
+	require.NoError(t, h.Wait(context.Background()))
+	require.True(t, afterCalled.Load())
+
+	slow := m.FireParallelHandle(&myEvent{s: "slow"}, func(Event) { panic("boom") })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, slow.Wait(ctx), context.DeadlineExceeded)
+	close(release)
+	<-slow.Done() // Done even though an after func panicked
+
+	require.NoError(t, Nop.FireParallelHandle(&myEvent{}).Wait(context.Background()))
+}
+
+func TestUnsubscribeStale(t *testing.T) {
+	m := New()
+	var called int
+	unsubscribe := Subscribe(m, 0, func(*myEvent) {})
+	m.Reset()
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	unsubscribe() // Stale after Reset, must not remove the new subscriber
+	m.Fire(&myEvent{})
+	require.Equal(t, 1, called)
+
+	unsubscribe = Subscribe(m, 0, func(*myEvent) {})
+	require.Equal(t, 2, m.UnsubscribeAll(&myEvent{}))
+	Subscribe(m, 0, func(*myEvent) { called++ })
+	unsubscribe() // Already removed by UnsubscribeAll
+	m.Fire(&myEvent{})
+	require.Equal(t, 2, called)
+}
+
+func TestUnsubscribeCrossManager(t *testing.T) {
+	m1, m2 := New(), New()
+	var called1, called2 int
+	s := SubscribeHandle(m1, 0, func(*myEvent, Subscription) { called1++ })
+	Subscribe(m2, 0, func(*myEvent) { called2++ })
+
+	// A subscriber of m1 is unknown to m2 even for the same type
+	m2.(*manager).unsubscribe(KeyOf[*myEvent](), s.sub)
+	m2.Fire(&myEvent{})
+	require.Equal(t, 1, called2)
+
+	s.Unsubscribe()
+	m1.Fire(&myEvent{})
+	m2.Fire(&myEvent{})
+	require.Zero(t, called1)
+	require.Equal(t, 2, called2)
+}
+
+func BenchmarkFireConcurrentReaders(b *testing.B) {
+	m := New()
+	Subscribe(m, 0, func(*myEvent) {})
+	SubscribeAll(m, 0, func(Event) {})
+	e := &myEvent{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Fire(e)
+		}
+	})
+}
+
+func BenchmarkFireWithConcurrentSubscribe(b *testing.B) {
+	m := New()
+	Subscribe(m, 0, func(*myEvent) {})
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				Subscribe(m, 0, func(*baseEvent) {})()
+			}
+		}
+	}()
+	defer close(done)
+	e := &myEvent{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Fire(e)
+		}
+	})
+}
+
+func TestSubscribeTTL(t *testing.T) {
+	m := New()
+	var called atomic.Int32
+	SubscribeTTL(m, 0, 10*time.Millisecond, func(*myEvent) { called.Add(1) })
+	m.Fire(&myEvent{})
+	require.EqualValues(t, 1, called.Load())
+	require.Eventually(t, func() bool { return !m.HasSubscriber(&myEvent{}) }, time.Second, time.Millisecond)
+	m.Fire(&myEvent{})
+	require.EqualValues(t, 1, called.Load())
+
+	// Unsubscribing early stops the timer
+	unsubscribe := SubscribeTTL(m, 0, time.Hour, func(*myEvent) { called.Add(1) })
+	unsubscribe()
+	unsubscribe()
+	m.Fire(&myEvent{})
+	require.EqualValues(t, 1, called.Load())
+
+	unsubscribe = SubscribeTTL(Nop, 0, time.Hour, func(*myEvent) {})
+	unsubscribe()
+}
+
+func TestSubscriberCount(t *testing.T) {
+	m := New()
+	require.Zero(t, m.SubscriberCount())
+	Subscribe(m, 0, func(*myEvent) {})
+	Subscribe(m, 0, func(*myEvent) {})
+	Subscribe(m, 0, func(*baseEvent) {})
+	SubscribeAll(m, 0, func(Event) {})
+	m.SubscribeTopic("a.*", 0, func(any) {})
+	require.Equal(t, 5, m.SubscriberCount())
+	require.Equal(t, 3, m.SubscriberCount(&myEvent{}, &baseEvent{}))
+	require.Equal(t, 1, m.SubscriberCount(nil))
+	require.Zero(t, m.SubscriberCount(&userCreated{}))
+	require.Zero(t, Nop.SubscriberCount())
+}
+
+func TestRegistrationStats(t *testing.T) {
+	m := New()
+	unsub := Subscribe(m, 0, func(*myEvent) {})
+	Subscribe(m, 0, func(*myEvent) {})
+	unsubTopic := m.SubscribeTopic("a.b", 0, func(any) {})
+	SubscribeBatch(m, SubscribeSpec{EventType: &baseEvent{}, Fn: func(Event) {}})
+	subscribed, unsubscribed := m.RegistrationStats()
+	require.EqualValues(t, 4, subscribed)
+	require.Zero(t, unsubscribed)
+
+	unsub()
+	unsub()
+	unsubTopic()
+	require.Equal(t, 1, m.UnsubscribeAll(&myEvent{}))
+	subscribed, unsubscribed = m.RegistrationStats()
+	require.EqualValues(t, 4, subscribed)
+	require.EqualValues(t, 3, unsubscribed)
+
+	m.Reset()
+	subscribed, unsubscribed = m.RegistrationStats()
+	require.EqualValues(t, 4, subscribed)
+	require.EqualValues(t, 4, unsubscribed)
+
+	subscribed, unsubscribed = Nop.RegistrationStats()
+	require.Zero(t, subscribed)
+	require.Zero(t, unsubscribed)
+}
+
+func TestFireParallelAfterSeesMutations(t *testing.T) {
+	m := New()
+	Subscribe(m, 1, func(e *myEvent) { e.s += "a" })
+	Subscribe(m, 0, func(e *myEvent) { e.s += "b" })
+
+	after := make(chan string, 1)
+	FireParallel(m, &myEvent{}, func(e *myEvent) { after <- e.s })
+	require.Equal(t, "ab", <-after)
+	require.Equal(t, "ab", (<-FireParallelChan(m, &myEvent{})).s)
+
+	// Untyped nil events still run the after handlers
+	done := make(chan error, 1)
+	FireParallel[error](m, nil, func(err error) { done <- err })
+	require.NoError(t, <-done)
+}
+
+func TestFireAll(t *testing.T) {
+	m := New()
+	var got []string
+	Subscribe(m, 0, func(e *myEvent) { got = append(got, e.s) })
+	SubscribeAll(m, 0, func(e Event) { got = append(got, "any") })
+	FireAll(m, []*myEvent{{s: "a"}, {s: "b"}})
+	require.Equal(t, []string{"any", "a", "any", "b"}, got)
+
+	got = nil
+	FireAll[Event](m, []Event{&myEvent{s: "c"}, &baseEvent{}})
+	require.Equal(t, []string{"any", "c", "any"}, got)
+	FireAll(Nop, []*myEvent{{}})
+}
+
+func BenchmarkFireAll(b *testing.B) {
+	m := New()
+	Subscribe(m, 0, func(*myEvent) {})
+	events := make([]*myEvent, 100)
+	for i := range events {
+		events[i] = &myEvent{}
+	}
+	b.Run("Fire", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, e := range events {
+				m.Fire(e)
+			}
+		}
+	})
+	b.Run("FireAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			FireAll(m, events)
+		}
+	})
+}
+
+func BenchmarkSubscriberListOne(b *testing.B) {
+	sub := &subscriber{fn: func(Event) {}}
+	var list *subscriberList
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		list = newSubscriberList(sub)
+	}
+	if len(list.load()) != 1 {
+		b.Fatal("want one subscriber")
+	}
+}
+
+func TestSubscribeFromHandler(t *testing.T) {
+	m := New(WithLifecycleEvents(true))
+	var changes int
+	Subscribe(m, 0, func(*SubscriberChanged) { changes++ })
+
+	var calls []string
+	var unsubscribeLater, unsubscribeSelf func()
+	Subscribe(m, 3, func(*myEvent) {
+		calls = append(calls, "subscriber")
+		Subscribe(m, 4, func(*myEvent) { calls = append(calls, "added") })
+		unsubscribeLater()
+	})
+	unsubscribeSelf = Subscribe(m, 2, func(*myEvent) {
+		calls = append(calls, "self")
+		unsubscribeSelf()
+	})
+	unsubscribeLater = Subscribe(m, 1, func(*myEvent) { calls = append(calls, "later") })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Fire(&myEvent{}) // Must not deadlock
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlock subscribing from within a handler")
+	}
+	require.Equal(t, []string{"subscriber", "self", "later"}, calls)
+	require.Equal(t, 6, changes)
+
+	calls = nil
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"added", "subscriber"}, calls)
+}
+
+func TestHasSubscriberGeneric(t *testing.T) {
+	m := New()
+	require.False(t, HasSubscriber[*myEvent](m))
+	unsubscribe := Subscribe(m, 0, func(*myEvent) {})
+	require.True(t, HasSubscriber[*myEvent](m))
+	require.False(t, HasSubscriber[*baseEvent](m))
+	require.False(t, HasSubscriber[Event](m))
+	unsubscribe()
+
+	SubscribeAll(m, 0, func(Event) {})
+	require.True(t, HasSubscriber[*baseEvent](m))
+	require.True(t, HasSubscriber[Event](m))
+	require.False(t, HasSubscriber[*myEvent](Nop))
+}
+
+func TestHasSubscriberGenericTypeKeyFunc(t *testing.T) {
+	created := reflect.TypeOf(struct{ created bool }{})
+	m := New(WithTypeKeyFunc(func(e Event) Type {
+		if _, ok := e.(namedEvent); ok {
+			return created
+		}
+		return nil
+	}))
+	Subscribe(m, 0, func(*createdV1) {})
+	require.True(t, HasSubscriber[*createdV1](m))
+	require.True(t, HasSubscriber[*createdV2](m))
+	require.False(t, HasSubscriber[*myEvent](m))
+}
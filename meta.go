@@ -0,0 +1,66 @@
+package event
+
+// Meta is a read-only view of the metadata of a fire, e.g. trace or correlation ids,
+// passed to handlers subscribed by SubscribeMeta. The zero value has no metadata.
+type Meta struct {
+	values map[string]any
+}
+
+// Get returns the value of the key and whether it is set.
+func (m Meta) Get(key string) (any, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Len returns the number of keys.
+func (m Meta) Len() int {
+	return len(m.values)
+}
+
+// Range calls fn for every key and value in unspecified order until fn returns false.
+func (m Meta) Range(fn func(key string, value any) bool) {
+	for k, v := range m.values {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// SubscribeMeta is like Subscribe for a handler also receiving the metadata of the fire,
+// which is only set by FireMeta and empty for all other fires. See Subscribe for more details.
+// Managers not created by New always pass empty metadata.
+func SubscribeMeta[T Event](mgr Subscriber, priority int, handler func(T, Meta)) (unsubscribe func()) {
+	var typ T
+	return subscribe(mgr, typ, &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev, Meta{})
+			}
+		},
+		metaFn: func(e Event, meta Meta) {
+			if ev, ok := e.(T); ok {
+				handler(ev, meta)
+			}
+		},
+		handler: handler,
+	})
+}
+
+func (m *manager) FireMeta(event Event, meta map[string]any) {
+	values := make(map[string]any, len(meta))
+	for k, v := range meta {
+		values[k] = v
+	}
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{meta: Meta{values: values}})
+}
+
+// metadata returns the metadata of the fire.
+func (o *fireOptions) metadata() Meta {
+	if o == nil {
+		return Meta{}
+	}
+	return o.meta
+}
@@ -0,0 +1,40 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFireMeta(t *testing.T) {
+	m := New()
+	var got []any
+	SubscribeMeta(m, 0, func(_ *myEvent, meta Meta) {
+		v, ok := meta.Get("traceID")
+		got = append(got, v)
+		require.Equal(t, ok, meta.Len() == 1)
+	})
+	var plain int
+	Subscribe(m, 0, func(*myEvent) { plain++ })
+
+	meta := map[string]any{"traceID": "abc"}
+	m.FireMeta(&myEvent{}, meta)
+	m.Fire(&myEvent{})
+	require.Equal(t, []any{"abc", nil}, got)
+	require.Equal(t, 2, plain)
+
+	// The metadata is copied at fire time
+	var seen Meta
+	SubscribeMeta(m, 0, func(_ *baseEvent, meta Meta) { seen = meta })
+	m.FireMeta(&baseEvent{}, meta)
+	meta["traceID"] = "changed"
+	v, _ := seen.Get("traceID")
+	require.Equal(t, "abc", v)
+
+	var keys []string
+	seen.Range(func(key string, _ any) bool {
+		keys = append(keys, key)
+		return true
+	})
+	require.Equal(t, []string{"traceID"}, keys)
+}
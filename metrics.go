@@ -0,0 +1,120 @@
+package event
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+)
+
+// MetricsRecorder records metrics of a Manager, e.g. to export them to a metrics backend.
+// Event types are passed by their name registered with RegisterType or their reflect.Type string.
+// Methods are called synchronously by the fire path and must be safe for concurrent use.
+type MetricsRecorder interface {
+	// EventFired is called after an event was fired with the duration of the whole fire,
+	// including hooks and all subscribers.
+	EventFired(eventType string, d time.Duration)
+	// SubscriberPanicked is called for every recovered panic of a subscriber with the
+	// name of the subscriber if subscribed by SubscribeNamed.
+	SubscriberPanicked(eventType, subscriber string)
+}
+
+// WithMetrics returns a ManagerOption that adds a MetricsRecorder.
+// Multiple recorders can be added by passing the option multiple times.
+func WithMetrics(r MetricsRecorder) ManagerOption {
+	return func(m *manager) {
+		m.metrics = append(m.metrics, r)
+	}
+}
+
+// WithExpvar returns a ManagerOption that publishes the number of fired events, recovered panics
+// and a fire latency summary per event type under the expvar name prefix, e.g. for /debug/vars.
+// Managers using the same prefix share the published variables.
+func WithExpvar(prefix string) ManagerOption {
+	return WithMetrics(newExpvarRecorder(prefix))
+}
+
+// expvarRecorder is a MetricsRecorder publishing to expvar.
+type expvarRecorder struct {
+	fired   *expvar.Map // Event type to number of fired events
+	panics  *expvar.Map // Event type to number of recovered panics
+	latency *expvar.Map // Event type to *latencySummary
+}
+
+var expvarMu sync.Mutex // Serializes publishing expvar maps
+
+func newExpvarRecorder(prefix string) *expvarRecorder {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	root, ok := expvar.Get(prefix).(*expvar.Map)
+	if !ok {
+		root = expvar.NewMap(prefix)
+	}
+	return &expvarRecorder{
+		fired:   childMap(root, "fired"),
+		panics:  childMap(root, "panics"),
+		latency: childMap(root, "latency"),
+	}
+}
+
+// childMap returns the map of the key in root and creates it if it does not exist.
+func childMap(root *expvar.Map, key string) *expvar.Map {
+	if child, ok := root.Get(key).(*expvar.Map); ok {
+		return child
+	}
+	child := new(expvar.Map)
+	root.Set(key, child)
+	return child
+}
+
+func (r *expvarRecorder) EventFired(eventType string, d time.Duration) {
+	r.fired.Add(eventType, 1)
+	s, ok := r.latency.Get(eventType).(*latencySummary)
+	if !ok {
+		expvarMu.Lock()
+		if s, ok = r.latency.Get(eventType).(*latencySummary); !ok {
+			s = new(latencySummary)
+			r.latency.Set(eventType, s)
+		}
+		expvarMu.Unlock()
+	}
+	s.observe(d)
+}
+
+func (r *expvarRecorder) SubscriberPanicked(eventType, _ string) {
+	r.panics.Add(eventType, 1)
+}
+
+// latencySummary is an expvar.Var summarizing fire durations.
+type latencySummary struct {
+	mu    sync.Mutex
+	count int64
+	sum   time.Duration
+	max   time.Duration
+}
+
+func (s *latencySummary) observe(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.sum += d
+	if d > s.max {
+		s.max = d
+	}
+}
+
+// String implements expvar.Var.
+func (s *latencySummary) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var mean time.Duration
+	if s.count != 0 {
+		mean = s.sum / time.Duration(s.count)
+	}
+	b, _ := json.Marshal(struct {
+		Count     int64 `json:"count"`
+		MeanNanos int64 `json:"meanNanos"`
+		MaxNanos  int64 `json:"maxNanos"`
+	}{s.count, int64(mean), int64(s.max)})
+	return string(b)
+}
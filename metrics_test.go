@@ -0,0 +1,67 @@
+package event
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRecorder struct {
+	mu     sync.Mutex
+	fired  []string
+	panics []string
+}
+
+func (r *fakeRecorder) EventFired(eventType string, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fired = append(r.fired, eventType)
+}
+
+func (r *fakeRecorder) SubscriberPanicked(eventType, subscriber string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.panics = append(r.panics, eventType+"/"+subscriber)
+}
+
+func TestMetrics(t *testing.T) {
+	r1, r2 := new(fakeRecorder), new(fakeRecorder)
+	m := New(WithMetrics(r1), WithMetrics(r2))
+	m.RegisterType("my", &myEvent{})
+	SubscribeNamed(m, "broken", 0, func(*myEvent) { panic("boom") })
+	m.Fire(&myEvent{})
+	m.Fire(jsonEvent{})
+
+	for _, r := range []*fakeRecorder{r1, r2} {
+		require.Equal(t, []string{"my", "event.jsonEvent"}, r.fired)
+		require.Equal(t, []string{"my/broken"}, r.panics)
+	}
+}
+
+func TestExpvar(t *testing.T) {
+	m := New(WithExpvar("event_test"))
+	New(WithExpvar("event_test")) // Reuses the published vars
+	root := expvar.Get("event_test").(*expvar.Map)
+	count := func(key string) (n int64) {
+		if v, ok := root.Get(key).(*expvar.Map).Get("*event.myEvent").(*expvar.Int); ok {
+			n = v.Value()
+		}
+		return n
+	}
+	fired, panics := count("fired"), count("panics") // Vars are global across test runs
+
+	Subscribe(m, 0, func(*myEvent) { panic("boom") })
+	m.Fire(&myEvent{})
+	m.Fire(&myEvent{})
+	require.Equal(t, fired+2, count("fired"))
+	require.Equal(t, panics+2, count("panics"))
+
+	var summary struct{ Count int64 }
+	latency := root.Get("latency").(*expvar.Map).Get("*event.myEvent").String()
+	require.NoError(t, json.Unmarshal([]byte(latency), &summary))
+	require.Equal(t, fired+2, summary.Count)
+}
@@ -0,0 +1,62 @@
+package event
+
+import "sync/atomic"
+
+// Middleware wraps the call of a subscriber, e.g. for logging, metrics or auth gates.
+// It calls next to call the subscriber or the next middleware, and may skip the subscriber by
+// not calling next. To also skip all remaining subscribers of the fire, it can stop the propagation
+// of the event with StopPropagation.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// WithMiddleware returns a ManagerOption that wraps every subscriber call with the middleware,
+// the first being the outermost. Multiple options append their middleware.
+// Middleware runs within panic recovery, after events of another type were skipped by WithStrictTypes.
+// Topic subscribers are wrapped as well and receive the payload.
+func WithMiddleware(mw ...Middleware) ManagerOption {
+	return func(m *manager) {
+		m.middleware = append(m.middleware, mw...)
+	}
+}
+
+// applyMiddleware returns the handler of the subscriber wrapped with the middleware.
+func (m *manager) applyMiddleware(sub *subscriber, opts *fireOptions) HandlerFunc {
+	h := HandlerFunc(func(e Event) { callHandler(sub, e, opts) })
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		h = m.middleware[i](h)
+	}
+	return h
+}
+
+// Stoppable is implemented by events whose propagation can be stopped, e.g. by embedding Propagation.
+// A fire calls no further subscribers once PropagationStopped returns true, including subscribers
+// of other lists like wildcard subscribers. Concurrent fires only skip subscribers not started yet.
+type Stoppable interface {
+	PropagationStopped() bool
+}
+
+// Propagation can be embedded by events to make them Stoppable. It is safe for concurrent use.
+// The zero value is not stopped.
+type Propagation struct {
+	stopped atomic.Bool
+}
+
+// StopPropagation stops the propagation of the event to further subscribers.
+func (p *Propagation) StopPropagation() {
+	p.stopped.Store(true)
+}
+
+// PropagationStopped reports whether StopPropagation was called.
+func (p *Propagation) PropagationStopped() bool {
+	return p.stopped.Load()
+}
+
+// StopPropagation stops the propagation of the event if it has a StopPropagation method,
+// e.g. by embedding Propagation, and reports whether it did. Middleware and subscribers
+// use it to skip all remaining subscribers of the fire.
+func StopPropagation(e Event) bool {
+	s, ok := e.(interface{ StopPropagation() })
+	if ok {
+		s.StopPropagation()
+	}
+	return ok
+}
@@ -0,0 +1,85 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type gatedEvent struct {
+	Propagation
+	user string
+}
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(e Event) {
+				calls = append(calls, name+" before")
+				next(e)
+				calls = append(calls, name+" after")
+			}
+		}
+	}
+	m := New(WithMiddleware(trace("outer")), WithMiddleware(trace("inner")))
+	Subscribe(m, 0, func(*myEvent) { calls = append(calls, "handler") })
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"outer before", "inner before", "handler", "inner after", "outer after"}, calls)
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	// Skips single subscribers by not calling next
+	skip := func(next HandlerFunc) HandlerFunc {
+		return func(e Event) {
+			if e, ok := e.(*myEvent); ok && e.s == "skip" {
+				return
+			}
+			next(e)
+		}
+	}
+	// Halts the chain for unauthorized users
+	auth := func(next HandlerFunc) HandlerFunc {
+		return func(e Event) {
+			if e, ok := e.(*gatedEvent); ok && e.user != "admin" {
+				StopPropagation(e)
+				return
+			}
+			next(e)
+		}
+	}
+	m := New(WithMiddleware(skip, auth))
+	var calls int
+	Subscribe(m, 1, func(*myEvent) { calls++ })
+	Subscribe(m, 1, func(*gatedEvent) { calls++ })
+	Subscribe(m, 0, func(*gatedEvent) { calls++ })
+	SubscribeAll(m, -1, func(Event) { calls++ })
+
+	m.Fire(&myEvent{s: "skip"})
+	require.Zero(t, calls)
+	m.Fire(&myEvent{})
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	guest := &gatedEvent{user: "guest"}
+	m.Fire(guest)
+	require.Zero(t, calls)
+	require.True(t, guest.PropagationStopped())
+	m.Fire(&gatedEvent{user: "admin"})
+	require.Equal(t, 3, calls)
+
+	require.False(t, StopPropagation(&myEvent{}))
+}
+
+func TestStopPropagationBySubscriber(t *testing.T) {
+	m := New()
+	var calls []string
+	Subscribe(m, 2, func(*gatedEvent) { calls = append(calls, "first") })
+	Subscribe(m, 1, func(e *gatedEvent) {
+		calls = append(calls, "stop")
+		e.StopPropagation()
+	})
+	Subscribe(m, 0, func(*gatedEvent) { calls = append(calls, "skipped") })
+	m.Fire(&gatedEvent{})
+	require.Equal(t, []string{"first", "stop"}, calls)
+}
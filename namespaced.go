@@ -0,0 +1,86 @@
+package event
+
+import (
+	"sort"
+	"sync"
+)
+
+// Namespaced maps namespaces to logically separate Managers, e.g. "auth" and "billing",
+// to pass a single object around in large applications.
+// Events fired to the Manager of one namespace are never seen by subscribers of another.
+type Namespaced struct {
+	opts []ManagerOption // Options for new Managers
+
+	mu    sync.Mutex         // Protects following fields
+	buses map[string]Manager // Namespace to Manager
+}
+
+// NewNamespaced returns a new Namespaced creating Managers with the given options.
+func NewNamespaced(opts ...ManagerOption) *Namespaced {
+	return &Namespaced{
+		opts:  opts,
+		buses: make(map[string]Manager),
+	}
+}
+
+// Bus returns the Manager of the namespace and creates it if it does not exist.
+func (n *Namespaced) Bus(name string) Manager {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	mgr, ok := n.buses[name]
+	if !ok {
+		mgr = New(n.opts...)
+		n.buses[name] = mgr
+	}
+	return mgr
+}
+
+// Namespaces returns the sorted names of all created namespaces.
+func (n *Namespaced) Namespaces() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	names := make([]string, 0, len(n.buses))
+	for name := range n.buses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Wait blocks until no event handlers are running in the given namespaces.
+// If no namespaces are specified it waits for all namespaces.
+func (n *Namespaced) Wait(namespaces ...string) {
+	for _, mgr := range n.managers(namespaces) {
+		mgr.Wait()
+	}
+}
+
+// UnsubscribeAll unsubscribes all subscribers in the given namespaces
+// and returns the number of subscribers unsubscribed.
+// If no namespaces are specified it unsubscribes all subscribers in all namespaces.
+func (n *Namespaced) UnsubscribeAll(namespaces ...string) int {
+	var count int
+	for _, mgr := range n.managers(namespaces) {
+		count += mgr.UnsubscribeAll()
+	}
+	return count
+}
+
+// managers returns the existing Managers of the namespaces or all if none are specified.
+func (n *Namespaced) managers(namespaces []string) []Manager {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var mgrs []Manager
+	if len(namespaces) == 0 {
+		for _, mgr := range n.buses {
+			mgrs = append(mgrs, mgr)
+		}
+		return mgrs
+	}
+	for _, name := range namespaces {
+		if mgr, ok := n.buses[name]; ok {
+			mgrs = append(mgrs, mgr)
+		}
+	}
+	return mgrs
+}
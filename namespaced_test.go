@@ -0,0 +1,33 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaced(t *testing.T) {
+	n := NewNamespaced()
+	auth, billing := n.Bus("auth"), n.Bus("billing")
+	require.Same(t, auth, n.Bus("auth"))
+	require.Equal(t, []string{"auth", "billing"}, n.Namespaces())
+
+	var authCalled, billingCalled int
+	Subscribe(auth, 0, func(*myEvent) { authCalled++ })
+	Subscribe(billing, 0, func(*myEvent) { billingCalled++ })
+	SubscribeAll(billing, 0, func(Event) { billingCalled++ })
+
+	auth.Fire(&myEvent{})
+	require.Equal(t, 1, authCalled)
+	require.Equal(t, 0, billingCalled)
+
+	billing.FireParallel(&myEvent{})
+	n.Wait()
+	require.Equal(t, 1, authCalled)
+	require.Equal(t, 2, billingCalled)
+
+	require.Equal(t, 2, n.UnsubscribeAll("billing", "unknown"))
+	require.True(t, auth.HasSubscriber(&myEvent{}))
+	require.Equal(t, 1, n.UnsubscribeAll())
+	require.False(t, auth.HasSubscriber())
+}
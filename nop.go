@@ -1,15 +1,107 @@
 package event
 
+import (
+	"context"
+	"time"
+)
+
 // Nop is an event Manager that does nothing.
+// It has no subscribers, so FireParallel only runs the after handlers.
 var Nop Manager = &nopMgr{}
 
 type nopMgr struct{}
 
+// closedPanicChan is the closed channel returned by Nop.PanicChan.
+var closedPanicChan = func() chan PanicError {
+	ch := make(chan PanicError)
+	close(ch)
+	return ch
+}()
+
+// IsNop reports whether mgr is Nop, e.g. for library code to skip building events entirely.
+// Unlike HasSubscriber it distinguishes Nop from a manager without subscribers.
+// Managers wrapping Nop are not reported.
+func IsNop(mgr Publisher) bool {
+	_, ok := mgr.(*nopMgr)
+	return ok
+}
+
 func (n *nopMgr) Subscribe(eventType Event, priority int, fn HandlerFunc) (unsubscribe func()) {
 	return func() {}
 }
-func (n *nopMgr) Wait(events ...Event)               {}
-func (n *nopMgr) HasSubscriber(events ...Event) bool { return false }
-func (n *nopMgr) UnsubscribeAll(events ...Event) int { return 0 }
-func (n *nopMgr) Fire(Event)                         {}
-func (n *nopMgr) FireParallel(Event, ...HandlerFunc) {}
+func (n *nopMgr) SubscribeTopic(string, int, func(any)) (unsubscribe func()) {
+	return func() {}
+}
+func (n *nopMgr) Wait(events ...Event)                               {}
+func (n *nopMgr) Drain(context.Context) error                        { return nil }
+func (n *nopMgr) Close() error                                       { return nil }
+func (n *nopMgr) PanicChan() <-chan PanicError                       { return closedPanicChan }
+func (n *nopMgr) QueueDepth() int                                    { return 0 }
+func (n *nopMgr) InFlight() int                                      { return 0 }
+func (n *nopMgr) Stats(Event) (int64, time.Time)                     { return 0, time.Time{} }
+func (n *nopMgr) SubscriberCount(...Event) int                       { return 0 }
+func (n *nopMgr) RegistrationStats() (int64, int64)                  { return 0, 0 }
+func (n *nopMgr) StuckHandlers(time.Duration) []HandlerInfo          { return nil }
+func (n *nopMgr) HasSubscriber(events ...Event) bool                 { return false }
+func (n *nopMgr) HasSubscriberType(Type) bool                        { return false }
+func (n *nopMgr) UnsubscribeAll(events ...Event) int                 { return 0 }
+func (n *nopMgr) UnsubscribeAllAndWait(events ...Event) (int, error) { return 0, nil }
+func (n *nopMgr) Fire(Event)                                         {}
+func (n *nopMgr) FireNoWildcard(Event)                               {}
+func (n *nopMgr) FireTopic(string, any)                              {}
+func (n *nopMgr) FireNoRecover(Event)                                {}
+func (n *nopMgr) FireTo(Event, func(Subscription) bool)              {}
+func (n *nopMgr) FireErr(Event) error                                { return nil }
+func (n *nopMgr) FireTagged(Event, func([]string) bool)              {}
+func (n *nopMgr) FireCtx(context.Context, Event)                     {}
+func (n *nopMgr) FireMeta(Event, map[string]any)                     {}
+func (n *nopMgr) FireLazy(Event, func() Event)                       {}
+func (n *nopMgr) FireRange(Event, int, int)                          {}
+func (n *nopMgr) FireConcurrent(Event)                               {}
+func (n *nopMgr) Pause(...Event)                                     {}
+func (n *nopMgr) Resume(...Event) int                                { return 0 }
+func (n *nopMgr) Refire(...Event) int                                { return 0 }
+func (n *nopMgr) Reset()                                             {}
+func (n *nopMgr) RegisterType(string, Event)                         {}
+func (n *nopMgr) TypeByName(string) (Type, bool)                     { return nil, false }
+func (n *nopMgr) FireJSON(string, []byte) error                      { return nil }
+
+func (n *nopMgr) FireParallel(event Event, after ...HandlerFunc) {
+	if len(after) == 0 {
+		return
+	}
+	go func() {
+		defer func() { _ = recover() }()
+		for _, fn := range after {
+			fn(event)
+		}
+	}()
+}
+
+func (n *nopMgr) FireParallelConcurrent(event Event, after ...HandlerFunc) {
+	n.FireParallel(event, after...)
+}
+
+func (n *nopMgr) FireParallelHandle(event Event, after ...HandlerFunc) *FireHandle {
+	h := &FireHandle{done: make(chan struct{})}
+	go func() {
+		defer close(h.done)
+		defer func() { _ = recover() }()
+		for _, fn := range after {
+			fn(event)
+		}
+	}()
+	return h
+}
+
+func (n *nopMgr) ParallelBatch() *ParallelBatch {
+	return &ParallelBatch{fire: func(event Event, after []HandlerFunc, done func()) {
+		go func() {
+			defer done()
+			defer func() { _ = recover() }()
+			for _, fn := range after {
+				fn(event)
+			}
+		}()
+	}}
+}
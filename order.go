@@ -0,0 +1,101 @@
+package event
+
+import "sort"
+
+// SubscribeAfter is like SubscribeNamed but the handler is only called after all subscribers of T named
+// one of after, regardless of priority, e.g. to order the stages of a pipeline. Unconstrained subscribers
+// keep the order of priority, and names not subscribed to T are ignored. The order is computed per event
+// type whenever its subscribers change. Cyclic dependencies are logged as error, and subscribers
+// in a cycle fall back to the order of priority. See Subscribe for more details.
+//
+// Dependencies only apply among subscribers of the same event type, not to wildcard subscribers.
+// Managers not created by New ignore the dependencies.
+func SubscribeAfter[T Event](mgr Subscriber, name string, priority int, after []string, handler func(T)) (unsubscribe func()) {
+	var typ T
+	return subscribe(mgr, typ, &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev)
+			}
+		},
+		handler: handler,
+		name:    name,
+		after:   append([]string(nil), after...),
+	})
+}
+
+// ordered returns subs in order of priority with the dependencies of SubscribeAfter applied.
+// Subscribers of equal priority are ordered by subscription. It may reorder subs in place.
+// The caller must hold m.mu.
+func (m *manager) ordered(eventType Type, subs []*subscriber) []*subscriber {
+	constrained := false
+	for _, sub := range subs {
+		if len(sub.after) != 0 {
+			constrained = true
+			break
+		}
+	}
+	less := func(i, j int) bool {
+		if subs[i].before(subs[j]) || subs[j].before(subs[i]) {
+			return subs[i].before(subs[j])
+		}
+		return subs[i].seq < subs[j].seq
+	}
+	if !constrained {
+		// Restore the order of priority if the last constrained subscriber was removed
+		if !sort.SliceIsSorted(subs, less) {
+			sort.Slice(subs, less)
+		}
+		return subs
+	}
+	sort.Slice(subs, less)
+
+	// Count the subscribers of each name not placed yet
+	pending := make(map[string]int, len(subs))
+	for _, sub := range subs {
+		if sub.name != "" {
+			pending[sub.name]++
+		}
+	}
+	ready := func(sub *subscriber) bool {
+		for _, name := range sub.after {
+			if name != sub.name && pending[name] != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Place the first ready subscriber in order of priority until all are placed
+	result := make([]*subscriber, 0, len(subs))
+	placed := make([]bool, len(subs))
+	for len(result) < len(subs) {
+		next := -1
+		for i, sub := range subs {
+			if !placed[i] && ready(sub) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			var cycle []string
+			for i, sub := range subs {
+				if !placed[i] {
+					cycle = append(cycle, sub.name)
+					result = append(result, sub)
+				}
+			}
+			m.log.Error(nil, "cyclic subscriber dependencies, falling back to order of priority",
+				"eventType", m.typeName(eventType),
+				"subscribers", cycle)
+			break
+		}
+		placed[next] = true
+		result = append(result, subs[next])
+		if name := subs[next].name; name != "" {
+			pending[name]--
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeAfter(t *testing.T) {
+	m := New()
+	var calls []string
+	record := func(name string) func(*myEvent) {
+		return func(*myEvent) { calls = append(calls, name) }
+	}
+	SubscribeAfter(m, "commit", 10, []string{"validate", "enrich"}, record("commit"))
+	SubscribeNamed(m, "validate", 0, record("validate"))
+	unsubscribe := SubscribeAfter(m, "enrich", 5, []string{"validate", "unknown"}, record("enrich"))
+	Subscribe(m, 1, record("unconstrained"))
+	Subscribe(m, 1, record("unconstrained2"))
+
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"unconstrained", "unconstrained2", "validate", "enrich", "commit"}, calls)
+
+	calls = nil
+	unsubscribe()
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"unconstrained", "unconstrained2", "validate", "commit"}, calls)
+}
+
+func TestSubscribeAfterUnsubscribeTarget(t *testing.T) {
+	m := New()
+	var calls []string
+	record := func(name string) func(*myEvent) {
+		return func(*myEvent) { calls = append(calls, name) }
+	}
+	Subscribe(m, 5, record("x"))
+	unsubscribeA := SubscribeNamed(m, "a", 0, record("a"))
+	unsubscribeC := SubscribeAfter(m, "c", 10, []string{"a"}, record("c"))
+	Subscribe(m, 7, record("y"))
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"y", "x", "a", "c"}, calls)
+
+	// The dependent moves back to its priority once its target is gone
+	calls = nil
+	unsubscribeA()
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"c", "y", "x"}, calls)
+
+	// Removing the only constrained subscriber restores the order of priority
+	calls = nil
+	SubscribeNamed(m, "a", 0, record("a"))
+	SubscribeAfter(m, "d", 6, []string{"a"}, record("d"))
+	unsubscribeC()
+	calls = nil
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"y", "x", "a", "d"}, calls)
+}
+
+func TestSubscribeAfterCycle(t *testing.T) {
+	var logs []string
+	m := New(WithLogger(funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})))
+	var calls []string
+	SubscribeAfter(m, "a", 0, []string{"b"}, func(*myEvent) { calls = append(calls, "a") })
+	SubscribeAfter(m, "b", 1, []string{"a"}, func(*myEvent) { calls = append(calls, "b") })
+	SubscribeAfter(m, "c", 2, []string{"d"}, func(*myEvent) { calls = append(calls, "c") })
+	SubscribeNamed(m, "d", -1, func(*myEvent) { calls = append(calls, "d") })
+
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"d", "c", "b", "a"}, calls)
+	require.NotEmpty(t, logs)
+	require.Contains(t, logs[len(logs)-1], "cyclic subscriber dependencies")
+}
@@ -0,0 +1,172 @@
+package event
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// panicChanBuffer is the buffer size of the channel returned by Manager.PanicChan.
+const panicChanBuffer = 64
+
+// PanicError is a panic recovered from an event subscriber or an after func, e.g. returned by
+// Publisher.FireErr and FireParallelErr or passed to the handler of WithPanicHandler.
+// Use errors.As to distinguish panics from errors returned by handlers.
+type PanicError struct {
+	Value    any    // The recovered value
+	Type     Type   // The event type of the fire, nil for wildcard keyed fires
+	Priority int    // The priority of the panicking subscriber, zero for after funcs
+	Stack    []byte // The stack of the panicking goroutine at the time of recovering
+}
+
+func (e *PanicError) Error() string {
+	t := "<nil>"
+	if e.Type != nil {
+		t = e.Type.String()
+	}
+	return fmt.Sprintf("recovered from panic by a handler of %s with priority %d: %v", t, e.Priority, e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error, so errors.Is and errors.As see through it.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// newPanicError returns a PanicError for the value recovered in the current goroutine.
+func newPanicError(r any, eventType Type, priority int) *PanicError {
+	return &PanicError{
+		Value:    r,
+		Type:     exportedType(eventType),
+		Priority: priority,
+		Stack:    debug.Stack(),
+	}
+}
+
+// WithPanicHandler returns a ManagerOption that calls fn with every panic recovered from a subscriber,
+// in addition to logging it, e.g. to report panics to an error tracker. It only applies if panics are
+// recovered, see WithRecoverPanic. The handler is called in the goroutine of the subscriber and must not panic.
+func WithPanicHandler(fn func(err *PanicError)) ManagerOption {
+	return func(m *manager) {
+		m.panicHandler = fn
+	}
+}
+
+// WithPanicIsolation returns a ManagerOption that isolates subscriber panics while panic recovery is
+// disabled by WithRecoverPanic. A panicking subscriber no longer aborts the fire: the remaining
+// subscribers and the after fire hooks are still called, and the first panic value is re-panicked in
+// the goroutine firing the event once the fire is done, so the manager state stays consistent and Wait
+// does not block on it. Later panics of the same fire are dropped. The stack of the original panic is lost.
+// It has no effect if panics are recovered.
+func WithPanicIsolation() ManagerOption {
+	return func(m *manager) {
+		m.panicIsolation = true
+	}
+}
+
+// isolatedPanic holds the first panic of a fire with panic isolation.
+type isolatedPanic struct {
+	mu       sync.Mutex // Protects following fields, needed for concurrent fires
+	panicked bool
+	value    any
+}
+
+// catch recovers a panic and keeps it if it is the first one. It must be deferred.
+func (p *isolatedPanic) catch() {
+	if r := recover(); r != nil {
+		p.mu.Lock()
+		if !p.panicked {
+			p.panicked, p.value = true, r
+		}
+		p.mu.Unlock()
+	}
+}
+
+// repanic re-panics the first caught panic, if any.
+func (p *isolatedPanic) repanic() {
+	p.mu.Lock()
+	panicked, value := p.panicked, p.value
+	p.mu.Unlock()
+	if panicked {
+		panic(value)
+	}
+}
+
+// withIsolatedPanic returns a copy of the options isolating subscriber panics.
+func withIsolatedPanic(opts *fireOptions) *fireOptions {
+	var o fireOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.isolated = new(isolatedPanic)
+	return &o
+}
+
+func (m *manager) FireErr(event Event) error {
+	if m.closed.Load() && m.postClose == PostCloseError {
+		return ErrClosed
+	}
+	var first *PanicError
+	func() {
+		m.enter()
+		defer m.exit()
+		m.fire(event, &fireOptions{onPanic: func(err *PanicError) {
+			if first == nil {
+				first = err
+			}
+		}})
+	}()
+	if first == nil {
+		return nil
+	}
+	return first
+}
+
+// reportPanic passes the panic recovered from the subscriber to all observers of panics.
+func (m *manager) reportPanic(r any, eventType Type, sub *subscriber, opts *fireOptions) {
+	onPanic := opts != nil && opts.onPanic != nil
+	if m.panicHandler == nil && !m.panicsUsed.Load() && m.draining.Load() == 0 && !onPanic {
+		return
+	}
+	err := newPanicError(r, eventType, sub.priority)
+	if m.panicHandler != nil {
+		m.panicHandler(err)
+	}
+	if m.panicsUsed.Load() {
+		m.emitPanic(err)
+	}
+	if m.draining.Load() != 0 {
+		m.drainPanic(sub, err)
+	}
+	if onPanic {
+		opts.onPanic(err)
+	}
+}
+
+func (m *manager) PanicChan() <-chan PanicError {
+	m.panicsUsed.Store(true)
+	return m.panics
+}
+
+// emitPanic sends the panic to the channel of PanicChan without blocking if it is used.
+func (m *manager) emitPanic(err *PanicError) {
+	m.panicsMu.Lock()
+	defer m.panicsMu.Unlock()
+	if m.panicsClosed {
+		return
+	}
+	select {
+	case m.panics <- *err:
+	default: // Dropped
+	}
+}
+
+// closePanics closes the channel of PanicChan.
+func (m *manager) closePanics() {
+	m.panicsMu.Lock()
+	defer m.panicsMu.Unlock()
+	if !m.panicsClosed {
+		m.panicsClosed = true
+		close(m.panics)
+	}
+}
@@ -0,0 +1,120 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFireErr(t *testing.T) {
+	errBoom := errors.New("boom")
+	var handled []*PanicError
+	m := New(WithRecoverPanic(false), WithPanicHandler(func(err *PanicError) { handled = append(handled, err) }))
+	var called int
+	Subscribe(m, 2, func(*myEvent) { panic(errBoom) })
+	Subscribe(m, 1, func(*myEvent) { panic("second") })
+	Subscribe(m, 0, func(*myEvent) { called++ })
+
+	err := m.FireErr(&myEvent{})
+	var pe *PanicError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, errBoom, pe.Value)
+	require.Equal(t, KeyOf[*myEvent](), pe.Type)
+	require.Equal(t, 2, pe.Priority)
+	require.Contains(t, string(pe.Stack), "panic_test.go")
+	require.ErrorIs(t, err, errBoom)
+	require.Equal(t, 1, called)
+	require.Len(t, handled, 2)
+	require.Same(t, pe, handled[0])
+
+	require.NoError(t, m.FireErr(&baseEvent{}))
+	require.NoError(t, Nop.FireErr(&myEvent{}))
+}
+
+func TestFireErrPanicInFlight(t *testing.T) {
+	m := New(WithPostCloseBehavior(PostClosePanic))
+	require.NoError(t, m.Close())
+	require.Panics(t, func() { _ = m.FireErr(&myEvent{}) })
+	require.Zero(t, m.InFlight())
+
+	m = New(WithRecoverPanic(false), WithPanicHandler(func(*PanicError) { panic("handler") }))
+	Subscribe(m, 0, func(*myEvent) { panic("boom") })
+	require.Panics(t, func() { _ = m.FireErr(&myEvent{}) })
+	require.Zero(t, m.InFlight())
+	require.NoError(t, m.Drain(context.Background()))
+}
+
+func TestPanicHandler(t *testing.T) {
+	var handled []*PanicError
+	m := New(WithPanicHandler(func(err *PanicError) { handled = append(handled, err) }))
+	Subscribe(m, 3, func(*myEvent) { panic("boom") })
+	m.Fire(&myEvent{})
+	require.Len(t, handled, 1)
+	require.Equal(t, "boom", handled[0].Value)
+	require.Nil(t, handled[0].Unwrap())
+	require.EqualError(t, handled[0], "recovered from panic by a handler of *event.myEvent with priority 3: boom")
+}
+
+func TestFireParallelErrPanicError(t *testing.T) {
+	err := <-FireParallelErr(New(), &myEvent{}, func(*myEvent) error { panic("boom") })
+	var pe *PanicError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, "boom", pe.Value)
+	require.Equal(t, KeyOf[*myEvent](), pe.Type)
+}
+
+func TestPanicChan(t *testing.T) {
+	m := New()
+	Subscribe(m, 1, func(*myEvent) { panic("boom") })
+	m.Fire(&myEvent{}) // Not sent before PanicChan is called
+
+	panics := m.PanicChan()
+	m.Fire(&myEvent{})
+	pe := <-panics
+	require.Equal(t, "boom", pe.Value)
+	require.Equal(t, 1, pe.Priority)
+
+	// Dropped while the buffer is full
+	for i := 0; i < panicChanBuffer+10; i++ {
+		m.Fire(&myEvent{})
+	}
+	require.Len(t, panics, panicChanBuffer)
+
+	require.NoError(t, m.Close())
+	var n int
+	for range panics {
+		n++
+	}
+	require.Equal(t, panicChanBuffer, n)
+
+	_, ok := <-Nop.PanicChan()
+	require.False(t, ok)
+}
+
+func TestPanicIsolation(t *testing.T) {
+	var afterFire int
+	m := New(WithRecoverPanic(false), WithPanicIsolation(),
+		WithAfterFire(func(Type, Event) { afterFire++ }))
+	var called int
+	Subscribe(m, 2, func(*myEvent) { panic("boom") })
+	Subscribe(m, 1, func(*myEvent) { panic("second") })
+	Subscribe(m, 0, func(*myEvent) { called++ })
+
+	require.PanicsWithValue(t, "boom", func() { m.Fire(&myEvent{}) })
+	require.Equal(t, 1, called)
+	require.Equal(t, 1, afterFire)
+	require.Zero(t, m.InFlight())
+	m.Wait(&myEvent{}) // Does not block
+	m.Wait()
+
+	require.Panics(t, func() { m.FireConcurrent(&myEvent{}) }) // Either panic may be first
+	require.Equal(t, 2, called)
+	m.Wait()
+
+	// Recovered panics are not re-panicked
+	m = New(WithPanicIsolation())
+	Subscribe(m, 0, func(*myEvent) { panic("boom") })
+	require.NotPanics(t, func() { m.Fire(&myEvent{}) })
+}
@@ -0,0 +1,40 @@
+package event
+
+import "sync"
+
+// ParallelBatch groups FireParallel calls to wait for only those, returned by Manager.ParallelBatch.
+// Unlike Manager.Wait it is not affected by unrelated fires running concurrently.
+// A ParallelBatch is safe for concurrent use.
+type ParallelBatch struct {
+	active activity // Running fires of the batch, may start concurrently with waiting
+	fire   func(event Event, after []HandlerFunc, done func())
+}
+
+// Fire fires an event in a new goroutine like Publisher.FireParallel and adds it to the batch.
+// The fire is done once all subscribers and after handlers are done, even if an after handler panicked.
+// A fire rejected by panicking, e.g. after Close with PostClosePanic, is not waited for.
+func (b *ParallelBatch) Fire(event Event, after ...HandlerFunc) {
+	b.active.start()
+	var once sync.Once
+	stop := func() { once.Do(b.active.stop) }
+	accepted := false
+	defer func() {
+		if !accepted {
+			stop()
+		}
+	}()
+	b.fire(event, after, stop)
+	accepted = true
+}
+
+// Wait blocks until all fires of the batch are done.
+// Fires added while waiting are waited for as well until the batch is idle once.
+func (b *ParallelBatch) Wait() {
+	b.active.wait()
+}
+
+func (m *manager) ParallelBatch() *ParallelBatch {
+	return &ParallelBatch{fire: func(event Event, after []HandlerFunc, done func()) {
+		m.fireParallel(event, nil, after, done)
+	}}
+}
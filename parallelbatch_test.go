@@ -0,0 +1,72 @@
+package event
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelBatch(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	defer close(release)
+	var handled atomic.Int32
+	Subscribe(m, 0, func(e *myEvent) {
+		if e.s == "unrelated" {
+			<-release
+			return
+		}
+		handled.Add(1)
+	})
+
+	// An unrelated fire blocking until the end of the test
+	m.FireParallel(&myEvent{s: "unrelated"})
+
+	b := m.ParallelBatch()
+	var after atomic.Int32
+	for i := 0; i < 10; i++ {
+		b.Fire(&myEvent{}, func(Event) { after.Add(1) })
+	}
+	b.Fire(&myEvent{}, func(Event) { panic("boom") })
+	b.Wait()
+	require.EqualValues(t, 11, handled.Load())
+	require.EqualValues(t, 10, after.Load())
+	require.Equal(t, 1, m.InFlight())
+
+	nb := Nop.ParallelBatch()
+	nb.Fire(&myEvent{}, func(Event) { after.Add(1) })
+	nb.Wait()
+	require.EqualValues(t, 11, after.Load())
+}
+
+func TestParallelBatchConcurrentWait(t *testing.T) {
+	m := New()
+	Subscribe(m, 0, func(*myEvent) { time.Sleep(time.Millisecond) })
+	b := m.ParallelBatch()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			b.Fire(&myEvent{})
+		}()
+		go func() {
+			defer wg.Done()
+			b.Wait()
+		}()
+	}
+	wg.Wait()
+	b.Wait()
+	require.Zero(t, m.InFlight())
+}
+
+func TestParallelBatchRejected(t *testing.T) {
+	m := New(WithPostCloseBehavior(PostClosePanic))
+	require.NoError(t, m.Close())
+	b := m.ParallelBatch()
+	require.Panics(t, func() { b.Fire(&myEvent{}) })
+	b.Wait() // Not blocked by the rejected fire
+}
@@ -0,0 +1,117 @@
+package event
+
+// pauseBuffer buffers the events fired for a paused event type.
+type pauseBuffer struct {
+	events  []pausedFire // Buffered fires in order of firing
+	dropped int          // Number of fires dropped since the buffer was full
+}
+
+// pausedFire is a fire buffered while its event type was paused.
+type pausedFire struct {
+	event Event
+	opts  fireOptions
+}
+
+// defaultPauseBuffer is the default number of events buffered per paused event type.
+const defaultPauseBuffer = 1000
+
+// WithPauseBuffer returns a ManagerOption that sets the maximum number of events
+// buffered per event type paused by Manager.Pause. Default is 1000.
+func WithPauseBuffer(n int) ManagerOption {
+	return func(m *manager) {
+		m.pauseBuffer = n
+	}
+}
+
+func (m *manager) Pause(events ...Event) {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	for _, event := range events {
+		eventType := m.typeOf(event)
+		if _, ok := m.paused[eventType]; ok {
+			continue
+		}
+		m.paused[eventType] = new(pauseBuffer)
+		m.pausedTypes.Add(1)
+	}
+}
+
+func (m *manager) Resume(events ...Event) (dropped int) {
+	for _, event := range events {
+		dropped += m.resume(m.typeOf(event))
+	}
+	return dropped
+}
+
+// resume flushes the buffer of the paused event type until it is empty and resumes the type.
+// Events fired while flushing are appended to the buffer, so the order of fires is kept.
+// Events are taken from the buffer one at a time, so if a subscriber panics the remaining
+// events stay buffered and the type stays paused until resumed again.
+func (m *manager) resume(eventType Type) (dropped int) {
+	for {
+		m.pauseMu.Lock()
+		b, ok := m.paused[eventType]
+		if !ok {
+			m.pauseMu.Unlock()
+			return dropped
+		}
+		dropped += b.dropped
+		b.dropped = 0
+		if len(b.events) == 0 {
+			delete(m.paused, eventType)
+			m.pausedTypes.Add(-1)
+			m.pauseMu.Unlock()
+			return dropped
+		}
+		f := b.events[0]
+		b.events[0] = pausedFire{} // Release the event
+		b.events = b.events[1:]
+		m.pauseMu.Unlock()
+
+		m.firePaused(&f)
+	}
+}
+
+// firePaused dispatches a fire taken from a pause buffer.
+func (m *manager) firePaused(f *pausedFire) {
+	m.enter()
+	defer m.exit()
+	m.queued.Add(-1)
+	m.fire(f.event, &f.opts)
+}
+
+// resetPaused drops the buffered events of all paused types and resumes them.
+func (m *manager) resetPaused() {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	for eventType, b := range m.paused {
+		m.queued.Add(-int64(len(b.events)))
+		delete(m.paused, eventType)
+		m.pausedTypes.Add(-1)
+	}
+}
+
+// buffer buffers the fire if its event type is paused and reports whether it did.
+func (m *manager) buffer(event Event, eventType Type, opts *fireOptions) bool {
+	if m.pausedTypes.Load() == 0 || (opts != nil && opts.unpaused) {
+		return false
+	}
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	b, ok := m.paused[eventType]
+	if !ok {
+		return false
+	}
+	if len(b.events) >= m.pauseBuffer {
+		b.dropped++
+		return true
+	}
+	f := pausedFire{event: event}
+	if opts != nil {
+		f.opts = *opts
+	}
+	f.opts.unpaused = true
+	b.events = append(b.events, f)
+	m.queued.Add(1)
+	return true
+}
@@ -0,0 +1,79 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseResume(t *testing.T) {
+	m := New(WithPauseBuffer(3))
+	var got []string
+	Subscribe(m, 0, func(e *myEvent) {
+		got = append(got, e.s)
+		if e.s == "a" {
+			m.Fire(&myEvent{s: "during"}) // Fired while flushing, dispatched after buffered events
+		}
+	})
+	Subscribe(m, 0, func(e jsonEvent) { got = append(got, e.Name) })
+
+	m.Pause(&myEvent{})
+	m.Pause(&myEvent{})
+	for _, s := range []string{"a", "b", "c", "dropped"} {
+		m.Fire(&myEvent{s: s})
+	}
+	m.Fire(jsonEvent{Name: "other"}) // Not paused
+	require.Equal(t, []string{"other"}, got)
+	fired, _ := m.Stats(&myEvent{})
+	require.Zero(t, fired)
+	require.Equal(t, 3, m.QueueDepth()) // Dropped fires are not queued
+
+	require.Equal(t, 1, m.Resume(&myEvent{}))
+	require.Equal(t, []string{"other", "a", "b", "c", "during"}, got)
+	require.Zero(t, m.QueueDepth())
+
+	got = nil
+	m.Fire(&myEvent{s: "live"})
+	require.Equal(t, []string{"live"}, got)
+	require.Zero(t, m.Resume(&myEvent{})) // Not paused
+}
+
+func TestPauseResumePanic(t *testing.T) {
+	m := New(WithRecoverPanic(false))
+	var got []string
+	Subscribe(m, 0, func(e *myEvent) {
+		if e.s == "panic" {
+			panic("boom")
+		}
+		got = append(got, e.s)
+	})
+
+	m.Pause(&myEvent{})
+	m.Fire(&myEvent{s: "a"})
+	m.Fire(&myEvent{s: "panic"})
+	m.Fire(&myEvent{s: "b"})
+	require.Panics(t, func() { m.Resume(&myEvent{}) })
+	require.Equal(t, []string{"a"}, got)
+	require.Zero(t, m.InFlight())
+	require.Equal(t, 1, m.QueueDepth())
+
+	// The remaining event stays buffered until resumed again
+	require.Zero(t, m.Resume(&myEvent{}))
+	require.Equal(t, []string{"a", "b"}, got)
+	require.Zero(t, m.QueueDepth())
+}
+
+func TestPauseReset(t *testing.T) {
+	m := New()
+	m.Pause(&myEvent{})
+	m.Fire(&myEvent{s: "dropped"})
+	m.Reset()
+	require.Zero(t, m.QueueDepth())
+
+	var got []string
+	Subscribe(m, 0, func(e *myEvent) { got = append(got, e.s) })
+	m.Fire(&myEvent{s: "a"})
+	require.Equal(t, []string{"a"}, got)
+	require.Zero(t, m.Resume(&myEvent{})) // Not paused anymore
+	require.Equal(t, []string{"a"}, got)
+}
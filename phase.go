@@ -0,0 +1,59 @@
+package event
+
+import "reflect"
+
+// Phase is an ordering band for subscribers as an alternative to arbitrary int priorities,
+// which tend to collide in large systems. Each phase maps to its own priority range,
+// so subscribers of different phases never interleave.
+type Phase int
+
+// Phases in order of execution.
+const (
+	PhaseFirst  Phase = 2
+	PhaseEarly  Phase = 1
+	PhaseNormal Phase = 0
+	PhaseLate   Phase = -1
+	PhaseLast   Phase = -2
+)
+
+// PhaseBand is the size of the priority range reserved for each phase.
+// The range of a phase p is [p.Priority()-PhaseBand/2, p.Priority()+PhaseBand/2).
+const PhaseBand = 1 << 24
+
+// Priority returns the priority subscribers of the phase are subscribed with.
+func (p Phase) Priority() int {
+	return int(p) * PhaseBand
+}
+
+// SubscribePhase subscribes a handler to the event type T within a phase.
+// Subscribers of the same phase run in order of subscription.
+// See Subscribe for more details.
+func SubscribePhase[T Event](mgr Subscriber, phase Phase, handler func(T)) (unsubscribe func()) {
+	return Subscribe(mgr, phase.Priority(), handler)
+}
+
+// DefaultPrioritizer is implemented by events with a natural handling order to suggest
+// the priority of subscribers subscribed by SubscribeDefault, e.g. returning PhaseEarly.Priority().
+// DefaultPriority must not depend on the event value, since it is called on a zero value.
+type DefaultPrioritizer interface {
+	DefaultPriority() int
+}
+
+// SubscribeDefault subscribes a handler to the event type T with the priority suggested by T
+// implementing DefaultPrioritizer, or priority 0 otherwise. See Subscribe for more details.
+func SubscribeDefault[T Event](mgr Subscriber, handler func(T)) (unsubscribe func()) {
+	return Subscribe(mgr, defaultPriority[T](), handler)
+}
+
+// defaultPriority returns the priority suggested by the zero value of T, using a new value for
+// pointer types so that methods with value receivers can be called.
+func defaultPriority[T Event]() int {
+	var e any = *new(T)
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() == reflect.Pointer {
+		e = reflect.New(t.Elem()).Interface()
+	}
+	if p, ok := e.(DefaultPrioritizer); ok {
+		return p.DefaultPriority()
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribePhase(t *testing.T) {
+	m := New()
+	var order []string
+	SubscribePhase(m, PhaseLast, func(*myEvent) { order = append(order, "last") })
+	SubscribePhase(m, PhaseNormal, func(*myEvent) { order = append(order, "normal1") })
+	Subscribe(m, 1000, func(*myEvent) { order = append(order, "int") })
+	SubscribePhase(m, PhaseFirst, func(*myEvent) { order = append(order, "first") })
+	SubscribePhase(m, PhaseNormal, func(*myEvent) { order = append(order, "normal2") })
+	SubscribePhase(m, PhaseEarly, func(*myEvent) { order = append(order, "early") })
+
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"first", "early", "int", "normal1", "normal2", "last"}, order)
+}
+
+type auditEvent struct{}
+
+func (auditEvent) DefaultPriority() int { return PhaseLate.Priority() }
+
+type validateEvent struct{}
+
+func (*validateEvent) DefaultPriority() int { return 10 }
+
+func TestSubscribeDefault(t *testing.T) {
+	m := New()
+	var order []string
+	SubscribeDefault(m, func(*auditEvent) { order = append(order, "audit") })
+	Subscribe(m, 0, func(*auditEvent) { order = append(order, "normal") })
+	m.Fire(&auditEvent{})
+	require.Equal(t, []string{"normal", "audit"}, order)
+
+	order = nil
+	Subscribe(m, 5, func(*validateEvent) { order = append(order, "normal") })
+	SubscribeDefault(m, func(*validateEvent) { order = append(order, "validate") })
+	SubscribeDefault(m, func(auditEvent) { order = append(order, "value") })
+	SubscribeDefault(m, func(*myEvent) { order = append(order, "fallback") })
+	Subscribe(m, -1, func(*myEvent) { order = append(order, "low") })
+	m.Fire(&validateEvent{})
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"validate", "normal", "fallback", "low"}, order)
+	require.Equal(t, PhaseLate.Priority(), defaultPriority[auditEvent]())
+}
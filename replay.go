@@ -0,0 +1,175 @@
+package event
+
+import "sync"
+
+// replayBuffer retains the most recent events of a type for SubscribeReplay.
+type replayBuffer struct {
+	size   int     // Maximum number of retained events
+	events []Event // Retained events in order of firing
+}
+
+// WithReplay returns a ManagerOption that retains the n most recently fired events of the event
+// type t, so that subscribers subscribed by SubscribeReplay receive them before live events.
+// Retained events are kept in memory until replaced by newer ones or removed by Reset.
+func WithReplay(t Type, n int) ManagerOption {
+	return func(m *manager) {
+		if m.replays == nil {
+			m.replays = make(map[Type]*replayBuffer)
+		}
+		m.replays[t] = &replayBuffer{size: n}
+	}
+}
+
+// SubscribeReplay is like Subscribe but first calls the handler with the events of type T retained
+// by WithReplay in order of firing, followed by live events without duplicates or gaps.
+// Without retained events of T it equals Subscribe. See Subscribe for more details.
+//
+// Live events fired while replaying are queued and passed to the handler after the replay in the
+// goroutine of SubscribeReplay, so those fires do not wait for the handler.
+// Managers not created by New do not replay.
+func SubscribeReplay[T Event](mgr Subscriber, priority int, handler func(T)) (unsubscribe func()) {
+	var typ T
+	m, ok := asManager(mgr)
+	if !ok {
+		return Subscribe(mgr, priority, handler)
+	}
+	eventType := m.typeOf(typ)
+	rb := m.replays[eventType]
+	if rb == nil {
+		return Subscribe(mgr, priority, handler)
+	}
+
+	r := &replayer[T]{handler: handler, replaying: true}
+	sub := &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				r.live(ev)
+			}
+		},
+		handler: handler,
+	}
+
+	// Take the retained events and subscribe atomically with recording fires of the type,
+	// so every fire is either retained or dispatched to the new subscriber.
+	m.replayMu.Lock()
+	retained := append([]Event(nil), rb.events...)
+	count := m.insert(eventType, sub)
+	m.replayMu.Unlock()
+	m.subscriberChanged(eventType, count)
+
+	replay := &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev)
+			}
+		},
+		name: sub.name,
+	}
+	for _, e := range retained {
+		m.callSubscriber(replay, e, nil)
+	}
+	for queued := r.next(); len(queued) != 0; queued = r.next() {
+		for _, e := range queued {
+			m.callSubscriber(replay, e, nil)
+		}
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { m.unsubscribe(eventType, sub) }) }
+}
+
+// replayer queues live events for a SubscribeReplay handler until the replay is done.
+type replayer[T Event] struct {
+	handler func(T)
+
+	mu        sync.Mutex // Protects following fields
+	replaying bool       // Whether retained events are being replayed
+	queued    []Event    // Live events fired while replaying
+}
+
+// live passes a live event to the handler or queues it while replaying.
+func (r *replayer[T]) live(e T) {
+	r.mu.Lock()
+	if r.replaying {
+		r.queued = append(r.queued, e)
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+	r.handler(e)
+}
+
+// next returns the queued live events, or none and ends the replay.
+func (r *replayer[T]) next() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queued := r.queued
+	r.queued = nil
+	if len(queued) == 0 {
+		r.replaying = false
+	}
+	return queued
+}
+
+func (m *manager) Refire(events ...Event) (count int) {
+	for _, event := range events {
+		eventType := m.typeOf(event)
+		rb := m.replays[eventType]
+		if rb == nil {
+			continue
+		}
+		m.replayMu.Lock()
+		var last Event
+		if n := len(rb.events); n != 0 {
+			last = rb.events[n-1]
+		}
+		m.replayMu.Unlock()
+		if last == nil {
+			continue
+		}
+		m.refire(last)
+		count++
+	}
+	return count
+}
+
+// refire dispatches a retained event without retaining it again.
+func (m *manager) refire(event Event) {
+	m.enter()
+	defer m.exit()
+	m.fire(event, &fireOptions{refire: true})
+}
+
+// record retains the event if its type has a replay buffer and returns the subscriber list of
+// the type with its subscribers at the time of recording, which the fire must dispatch to.
+// Refired events are not retained again.
+func (m *manager) record(event Event, eventType Type, opts *fireOptions) (list *subscriberList, subs []*subscriber, ok bool) {
+	rb := m.replays[eventType]
+	if rb == nil || (opts != nil && opts.refire) {
+		return nil, nil, false
+	}
+	m.replayMu.Lock()
+	defer m.replayMu.Unlock()
+	if rb.size > 0 {
+		if len(rb.events) == rb.size {
+			copy(rb.events, rb.events[1:])
+			rb.events = rb.events[:len(rb.events)-1]
+		}
+		rb.events = append(rb.events, event)
+	}
+	if list = typedList(&m.subscribers, eventType); list != nil {
+		subs = list.load()
+	}
+	return list, subs, true
+}
+
+// resetReplays removes all retained events.
+func (m *manager) resetReplays() {
+	m.replayMu.Lock()
+	defer m.replayMu.Unlock()
+	for _, rb := range m.replays {
+		rb.events = nil
+	}
+}
@@ -0,0 +1,99 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeReplay(t *testing.T) {
+	m := New(WithReplay(typeOf(&myEvent{}), 2))
+	for _, s := range []string{"a", "b", "c"} {
+		m.Fire(&myEvent{s: s})
+	}
+
+	var got []string
+	SubscribeReplay(m, 0, func(e *myEvent) {
+		got = append(got, e.s)
+		if e.s == "b" {
+			m.Fire(&myEvent{s: "during"}) // Queued until the replay is done
+		}
+	})
+	require.Equal(t, []string{"b", "c", "during"}, got)
+
+	m.Fire(&myEvent{s: "live"})
+	require.Equal(t, []string{"b", "c", "during", "live"}, got)
+
+	m.Reset()
+	got = nil
+	SubscribeReplay(m, 0, func(e *myEvent) { got = append(got, e.s) })
+	require.Empty(t, got)
+
+	// Types without replay buffer are subscribed normally
+	var called int
+	SubscribeReplay(m, 0, func(jsonEvent) { called++ })
+	m.Fire(jsonEvent{})
+	require.Equal(t, 1, called)
+}
+
+func TestSubscribeReplayConcurrent(t *testing.T) {
+	const n = 1000
+	m := New(WithReplay(typeOf(&myEvent{}), n))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			m.Fire(&myEvent{s: fmt.Sprint(i)})
+		}
+	}()
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	SubscribeReplay(m, 0, func(e *myEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e.s)
+	})
+	wg.Wait()
+
+	// Every event is received exactly once and in order
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, got, n)
+	for i, s := range got {
+		require.Equal(t, fmt.Sprint(i), s)
+	}
+}
+
+func TestRefire(t *testing.T) {
+	m := New(WithReplay(typeOf(&myEvent{}), 2))
+	require.Zero(t, m.Refire(&myEvent{}, &baseEvent{}))
+
+	m.Fire(&myEvent{s: "a"})
+	m.Fire(&myEvent{s: "b"})
+	var got []string
+	Subscribe(m, 0, func(e *myEvent) { got = append(got, e.s) })
+	require.Equal(t, 1, m.Refire(&myEvent{}, &baseEvent{}))
+	require.Equal(t, []string{"b"}, got)
+
+	// Refired events are not retained again
+	got = nil
+	SubscribeReplay(m, 0, func(e *myEvent) { got = append(got, e.s) })
+	require.Equal(t, []string{"a", "b"}, got)
+
+	m.Reset()
+	require.Zero(t, m.Refire(&myEvent{}))
+}
+
+func TestRefirePanicInFlight(t *testing.T) {
+	m := New(WithReplay(typeOf(&myEvent{}), 1), WithRecoverPanic(false))
+	m.Fire(&myEvent{})
+	Subscribe(m, 0, func(*myEvent) { panic("boom") })
+	require.Panics(t, func() { m.Refire(&myEvent{}) })
+	require.Zero(t, m.InFlight())
+}
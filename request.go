@@ -0,0 +1,91 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Errors returned by Request.
+var (
+	// ErrNoReply is returned when all subscribers handled a request without replying.
+	ErrNoReply = errors.New("no reply to request")
+	// ErrRequestTimeout is returned when no reply to a request arrived in time.
+	ErrRequestTimeout = errors.New("request timed out")
+	// ErrRequestUnsupported is returned for managers that cannot correlate replies.
+	ErrRequestUnsupported = errors.New("requests not supported by manager")
+)
+
+type requestKey struct{}
+
+// pendingRequest awaits the reply to a request.
+type pendingRequest struct {
+	replied atomic.Bool // Whether a reply was accepted
+	reply   chan Event  // Receives the accepted reply
+}
+
+// Request fires the request event in a new goroutine like FireParallel with a context
+// and returns the first response passed to Reply with that context by a subscriber.
+// It returns ErrNoReply if all subscribers are done without a reply
+// and ErrRequestTimeout if neither happened within timeout, measured by the Clock of the manager.
+//
+// Requests are correlated by the context of the fire, which subscribers receive by SubscribeCtx,
+// so every call is independent of other requests, even of equal request values on other managers.
+//
+// Managers not created by New, nor wrapping one by an Unwrap method like eventtest.Recorder, do not
+// pass the context of a fire to subscribers and return ErrRequestUnsupported without firing.
+func Request[Req, Resp Event](mgr Publisher, req Req, timeout time.Duration) (resp Resp, err error) {
+	m, ok := asManager(mgr)
+	if !ok {
+		return resp, fmt.Errorf("%w: %T", ErrRequestUnsupported, mgr)
+	}
+	p := &pendingRequest{reply: make(chan Event, 1)}
+	ctx := context.WithValue(context.Background(), requestKey{}, p)
+
+	done := make(chan struct{})
+	m.fireParallel(req, &fireOptions{ctx: ctx}, nil, func() { close(done) })
+
+	timedOut := make(chan struct{})
+	timer := m.clock.AfterFunc(timeout, func() { close(timedOut) })
+	defer timer.Stop()
+	select {
+	case r := <-p.reply:
+		return replyAs[Resp](r)
+	case <-done:
+		if p.replied.CompareAndSwap(false, true) {
+			return resp, ErrNoReply
+		}
+	case <-timedOut:
+		if p.replied.CompareAndSwap(false, true) {
+			return resp, fmt.Errorf("%w after %s", ErrRequestTimeout, timeout)
+		}
+	}
+	// A reply was accepted concurrently
+	return replyAs[Resp](<-p.reply)
+}
+
+// replyAs returns the reply as Resp.
+func replyAs[Resp Event](r Event) (Resp, error) {
+	resp, ok := r.(Resp)
+	if !ok {
+		return resp, fmt.Errorf("reply of type %T is not a %T", r, resp)
+	}
+	return resp, nil
+}
+
+// Reply passes the response to the Request call that fired with ctx, or a context derived from it,
+// and reports whether it was accepted. Only the first reply to a request is accepted, and replies
+// after Request returned or with a context not from a Request are not.
+func Reply(ctx context.Context, resp Event) bool {
+	p, ok := ctx.Value(requestKey{}).(*pendingRequest)
+	if !ok {
+		return false
+	}
+	if !p.replied.CompareAndSwap(false, true) {
+		return false
+	}
+	p.reply <- resp
+	return true
+}
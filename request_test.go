@@ -0,0 +1,79 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pingRequest struct{ n int }
+
+type pongResponse struct{ n int }
+
+func TestRequest(t *testing.T) {
+	m := New()
+	SubscribeCtx(m, 1, func(ctx context.Context, req *pingRequest) {
+		require.True(t, Reply(ctx, &pongResponse{n: req.n + 1}))
+	})
+	SubscribeCtx(m, 0, func(ctx context.Context, req *pingRequest) {
+		require.False(t, Reply(ctx, &pongResponse{})) // Only the first reply is accepted
+	})
+
+	resp, err := Request[*pingRequest, *pongResponse](m, &pingRequest{n: 1}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.n)
+	require.False(t, Reply(context.Background(), &pongResponse{})) // Not from a request
+
+	_, err = Request[*pingRequest, *myEvent](m, &pingRequest{}, time.Second)
+	require.Error(t, err)
+}
+
+func TestRequestValues(t *testing.T) {
+	// Equal and non-comparable request values are correlated per call
+	type sliceRequest []int
+	m1, m2 := New(), New()
+	for i, m := range []Manager{m1, m2} {
+		i := i
+		SubscribeCtx(m, 0, func(ctx context.Context, req sliceRequest) {
+			time.Sleep(10 * time.Millisecond)
+			Reply(ctx, i+len(req))
+		})
+	}
+	results := make(chan int, 2)
+	for _, m := range []Manager{m1, m2} {
+		m := m
+		go func() {
+			resp, _ := Request[sliceRequest, int](m, sliceRequest{1}, time.Second)
+			results <- resp
+		}()
+	}
+	require.ElementsMatch(t, []int{1, 2}, []int{<-results, <-results})
+}
+
+func TestRequestNoReply(t *testing.T) {
+	m := New()
+	_, err := Request[*pingRequest, *pongResponse](m, &pingRequest{}, time.Hour)
+	require.True(t, errors.Is(err, ErrNoReply))
+
+	release := make(chan struct{})
+	var late bool
+	done := make(chan struct{})
+	SubscribeCtx(m, 0, func(ctx context.Context, _ *pingRequest) {
+		<-release
+		late = Reply(ctx, &pongResponse{})
+		close(done)
+	})
+	_, err = Request[*pingRequest, *pongResponse](m, &pingRequest{}, 10*time.Millisecond)
+	require.True(t, errors.Is(err, ErrRequestTimeout))
+	close(release)
+	<-done
+	require.False(t, late) // Replies after timing out are not accepted
+}
+
+func TestRequestUnsupported(t *testing.T) {
+	_, err := Request[*pingRequest, *pongResponse](Nop, &pingRequest{}, time.Hour)
+	require.ErrorIs(t, err, ErrRequestUnsupported)
+}
@@ -0,0 +1,77 @@
+package event
+
+import "sync"
+
+// Router dispatches events of type T sharing a Go type to handlers by a discriminator,
+// e.g. commands by their name, as a layer over a single subscriber of T.
+// A Router is safe for concurrent use.
+type Router[T Event, K comparable] struct {
+	key         func(T) K
+	unsubscribe func()
+
+	mu     sync.RWMutex // Protects following fields
+	routes map[K][]*route[T]
+}
+
+// route is a handler of a Router.
+type route[T Event] struct {
+	handler func(T)
+}
+
+// NewRouter returns a new Router subscribed to T with the priority that calls the handlers
+// registered by Handle for the key returned by key for each fired event.
+// Events without a handler for their key are ignored. See Subscribe for more details.
+func NewRouter[T Event, K comparable](mgr Subscriber, priority int, key func(T) K) *Router[T, K] {
+	r := &Router[T, K]{key: key, routes: make(map[K][]*route[T])}
+	r.unsubscribe = Subscribe(mgr, priority, r.dispatch)
+	return r
+}
+
+// Handle registers a handler for events with the key and returns a func to remove it.
+// Handlers of the same key are called in order of registration. A panicking handler is
+// recovered by the manager like a subscriber, which skips the remaining handlers of the key.
+// Like subscribers, handlers registered or removed while dispatching take effect on the next fire.
+func (r *Router[T, K]) Handle(key K, handler func(T)) (unsubscribe func()) {
+	rt := &route[T]{handler: handler}
+	r.mu.Lock()
+	old := r.routes[key]
+	routes := make([]*route[T], 0, len(old)+1)
+	r.routes[key] = append(append(routes, old...), rt)
+	r.mu.Unlock()
+
+	var once sync.Once
+	return func() { once.Do(func() { r.remove(key, rt) }) }
+}
+
+// remove removes the route of the key.
+func (r *Router[T, K]) remove(key K, rt *route[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.routes[key]
+	routes := make([]*route[T], 0, len(old))
+	for _, o := range old {
+		if o != rt {
+			routes = append(routes, o)
+		}
+	}
+	if len(routes) == 0 {
+		delete(r.routes, key)
+		return
+	}
+	r.routes[key] = routes
+}
+
+// Close unsubscribes the Router from the manager. Registered handlers are no longer called.
+func (r *Router[T, K]) Close() {
+	r.unsubscribe()
+}
+
+// dispatch calls the handlers of the event's key.
+func (r *Router[T, K]) dispatch(e T) {
+	r.mu.RLock()
+	routes := r.routes[r.key(e)]
+	r.mu.RUnlock()
+	for _, rt := range routes {
+		rt.handler(e)
+	}
+}
@@ -0,0 +1,38 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type command struct {
+	Name string
+	Arg  string
+}
+
+func TestRouter(t *testing.T) {
+	m := New()
+	r := NewRouter(m, 0, func(c *command) string { return c.Name })
+	var calls []string
+	r.Handle("greet", func(c *command) { calls = append(calls, "hello "+c.Arg) })
+	unsubscribe := r.Handle("greet", func(c *command) { calls = append(calls, "hi "+c.Arg) })
+	r.Handle("quit", func(c *command) { calls = append(calls, "bye") })
+
+	m.Fire(&command{Name: "greet", Arg: "bob"})
+	m.Fire(&command{Name: "unknown"})
+	m.Fire(&command{Name: "quit"})
+	require.Equal(t, []string{"hello bob", "hi bob", "bye"}, calls)
+
+	calls = nil
+	unsubscribe()
+	unsubscribe()
+	m.Fire(&command{Name: "greet", Arg: "alice"})
+	require.Equal(t, []string{"hello alice"}, calls)
+
+	calls = nil
+	r.Close()
+	m.Fire(&command{Name: "greet"})
+	require.Empty(t, calls)
+	require.False(t, m.HasSubscriber(&command{}))
+}
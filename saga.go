@@ -0,0 +1,40 @@
+package event
+
+import "fmt"
+
+// SagaStep is a step of a saga fired by FireSaga.
+type SagaStep struct {
+	Event      Event // The event fired to perform the step
+	Compensate Event // Optional event fired to roll the step back if a later step fails
+}
+
+// Failer is implemented by events whose subscribers can signal a failure,
+// e.g. by setting an error field of the event returned by Err.
+type Failer interface {
+	// Err returns the failure signaled by subscribers or nil.
+	Err() error
+}
+
+// FireSaga fires the events of the steps in order like FireErr, stopping at the first step failed.
+// A step failed if FireErr returned an error, e.g. the *PanicError of a panicking subscriber, or if
+// its event implements Failer and Err returns an error after firing. The compensation events of all
+// previous steps are then fired in reverse order and the error of the failed step is returned.
+// Compensation events are fired like Fire regardless of their Err.
+func FireSaga(mgr Publisher, steps ...SagaStep) error {
+	for i, step := range steps {
+		err := mgr.FireErr(step.Event)
+		if f, ok := step.Event.(Failer); ok && err == nil {
+			err = f.Err()
+		}
+		if err == nil {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if steps[j].Compensate != nil {
+				mgr.Fire(steps[j].Compensate)
+			}
+		}
+		return fmt.Errorf("saga step %d (%T) failed: %w", i, step.Event, err)
+	}
+	return nil
+}
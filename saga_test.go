@@ -0,0 +1,65 @@
+package event
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sagaEvent struct {
+	name string
+	err  error
+}
+
+func (e *sagaEvent) Err() error { return e.err }
+
+func TestFireSaga(t *testing.T) {
+	m := New()
+	var fired []string
+	errPayment := errors.New("payment declined")
+	Subscribe(m, 0, func(e *sagaEvent) {
+		fired = append(fired, e.name)
+		if e.name == "charge" {
+			e.err = errPayment
+		}
+	})
+	Subscribe(m, 0, func(e *myEvent) { fired = append(fired, e.s) })
+
+	step := func(name string) SagaStep {
+		return SagaStep{Event: &sagaEvent{name: name}, Compensate: &sagaEvent{name: "undo " + name}}
+	}
+	err := FireSaga(m,
+		step("reserve"),
+		SagaStep{Event: &myEvent{s: "notify"}}, // No failure signal nor compensation
+		step("charge"),
+		step("ship"),
+	)
+	require.True(t, errors.Is(err, errPayment))
+	require.Equal(t, []string{"reserve", "notify", "charge", "undo reserve"}, fired)
+
+	fired = nil
+	require.NoError(t, FireSaga(m, step("reserve"), step("ship")))
+	require.Equal(t, []string{"reserve", "ship"}, fired)
+}
+
+func TestFireSagaPanic(t *testing.T) {
+	m := New()
+	var fired []string
+	Subscribe(m, 0, func(e *sagaEvent) {
+		fired = append(fired, e.name)
+		if e.name == "charge" {
+			panic("payment service down")
+		}
+	})
+
+	err := FireSaga(m,
+		SagaStep{Event: &sagaEvent{name: "reserve"}, Compensate: &sagaEvent{name: "undo reserve"}},
+		SagaStep{Event: &sagaEvent{name: "charge"}},
+		SagaStep{Event: &sagaEvent{name: "ship"}},
+	)
+	var pe *PanicError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, "payment service down", pe.Value)
+	require.Equal(t, []string{"reserve", "charge", "undo reserve"}, fired)
+}
@@ -0,0 +1,112 @@
+package event
+
+// Subscription is a handle to a subscriber passed to handlers subscribed by SubscribeHandle,
+// e.g. for a handler to adapt its own priority. The zero value does nothing.
+type Subscription struct {
+	m         *manager
+	eventType Type
+	sub       *subscriber
+}
+
+// SubscribeHandle is like Subscribe but also passes the handle of the subscription to
+// the handler and returns it. See Subscribe for more details.
+//
+// Managers not created by New pass and return a zero Subscription.
+func SubscribeHandle[T Event](mgr Subscriber, priority int, handler func(T, Subscription)) Subscription {
+	var typ T
+	m, ok := asManager(mgr)
+	if !ok {
+		Subscribe(mgr, priority, func(e T) { handler(e, Subscription{}) })
+		return Subscription{}
+	}
+	s := Subscription{m: m, eventType: m.typeOf(typ)}
+	s.sub = &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ev, s)
+			}
+		},
+		handler: handler,
+	}
+	m.subscribe(s.eventType, s.sub)
+	return s
+}
+
+// Type returns the event type subscribed to, nil for wildcard subscribers.
+func (s Subscription) Type() Type {
+	return exportedType(s.eventType)
+}
+
+// Name returns the name of the subscriber if subscribed by SubscribeNamed.
+func (s Subscription) Name() string {
+	if s.sub == nil {
+		return ""
+	}
+	return s.sub.name
+}
+
+// Priority returns the current priority of the subscriber.
+func (s Subscription) Priority() int {
+	if s.sub == nil {
+		return 0
+	}
+	if cur := s.current(); cur != nil {
+		return cur.priority
+	}
+	return s.sub.priority
+}
+
+// SetPriority changes the priority of the subscriber and reports whether it is still subscribed.
+// The change applies to fires started afterwards. Fires already running, like the one calling
+// a handler that changes its own priority, keep calling the subscribers in the old order.
+func (s Subscription) SetPriority(priority int) bool {
+	if s.sub == nil {
+		return false
+	}
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	cur := s.current()
+	if cur == nil {
+		return false
+	}
+	if cur.priority == priority {
+		return true
+	}
+	list := s.m.list(s.eventType)
+	subs := make([]*subscriber, 0, len(list.load()))
+	for _, sub := range list.load() {
+		if sub != cur {
+			subs = append(subs, sub)
+		}
+	}
+	moved := *cur
+	moved.priority = priority
+	moved.origin = cur.identity()
+	s.m.subSeq++
+	moved.seq = s.m.subSeq
+	list.store(s.m.ordered(s.eventType, insertSorted(subs, &moved)))
+	return true
+}
+
+// Unsubscribe unsubscribes the subscriber. Subsequent calls do nothing.
+func (s Subscription) Unsubscribe() {
+	if s.sub == nil {
+		return
+	}
+	s.m.unsubscribe(s.eventType, s.sub)
+}
+
+// current returns the subscriber of the subscription currently in the list or nil if it was removed.
+func (s Subscription) current() *subscriber {
+	list := s.m.list(s.eventType)
+	if list == nil {
+		return nil
+	}
+	for _, sub := range list.load() {
+		if sub.identity() == s.sub {
+			return sub
+		}
+	}
+	return nil
+}
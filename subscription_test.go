@@ -0,0 +1,74 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionSetPriority(t *testing.T) {
+	m := New()
+	var order []string
+	Subscribe(m, 5, func(*myEvent) { order = append(order, "mid") })
+	s := SubscribeHandle(m, 10, func(_ *myEvent, s Subscription) {
+		order = append(order, "adaptive")
+		require.True(t, s.SetPriority(0))
+	})
+	Subscribe(m, 1, func(*myEvent) { order = append(order, "low") })
+	require.Equal(t, 10, s.Priority())
+	require.Equal(t, typeOf(&myEvent{}), s.Type())
+
+	m.Fire(&myEvent{}) // Running fire keeps the old order
+	require.Equal(t, []string{"adaptive", "mid", "low"}, order)
+	require.Equal(t, 0, s.Priority())
+
+	order = nil
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"mid", "low", "adaptive"}, order)
+
+	order = nil
+	s.Unsubscribe()
+	s.Unsubscribe()
+	require.False(t, s.SetPriority(3))
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"mid", "low"}, order)
+}
+
+func TestSubscriptionZero(t *testing.T) {
+	var called int
+	s := SubscribeHandle(Nop, 0, func(*myEvent, Subscription) { called++ })
+	require.Equal(t, Subscription{}, s)
+	require.False(t, s.SetPriority(1))
+	require.Zero(t, s.Priority())
+	require.Nil(t, s.Type())
+	s.Unsubscribe()
+}
+
+func TestFireTo(t *testing.T) {
+	m := New()
+	var calls []string
+	SubscribeNamed(m, "a", 2, func(*myEvent) { calls = append(calls, "a") })
+	SubscribeNamed(m, "b", 1, func(*myEvent) { calls = append(calls, "b") })
+	SubscribeNamed(m, "c", 0, func(*myEvent) { calls = append(calls, "c") })
+	SubscribeAll(m, 3, func(Event) { calls = append(calls, "any") })
+
+	var types []Type
+	m.FireTo(&myEvent{}, func(s Subscription) bool {
+		types = append(types, s.Type())
+		return s.Name() != "b"
+	})
+	require.Equal(t, []string{"any", "a", "c"}, calls)
+	require.Equal(t, []Type{nil, KeyOf[*myEvent](), KeyOf[*myEvent](), KeyOf[*myEvent]()}, types)
+
+	// The handle can be used to act on the subscriber
+	calls = nil
+	m.FireTo(&myEvent{}, func(s Subscription) bool {
+		if s.Name() == "c" {
+			s.Unsubscribe()
+		}
+		return false
+	})
+	require.Empty(t, calls)
+	m.Fire(&myEvent{})
+	require.Equal(t, []string{"any", "a", "b"}, calls)
+}
@@ -0,0 +1,162 @@
+package event
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Topic wildcard segments.
+const (
+	topicAnyOne  = "*"  // Matches exactly one segment
+	topicAnyMany = "**" // Matches one or more trailing segments, only valid as last segment
+)
+
+// topicNode is a node of the topic trie, one per topic segment.
+type topicNode struct {
+	children map[string]*topicNode
+	subs     []*topicSub // Subscribers of the pattern ending at this node, replaced on change
+}
+
+// topicSub is a subscriber of a topic pattern.
+type topicSub struct {
+	sub   *subscriber
+	exact bool   // Whether the pattern has no wildcard segments
+	seq   uint64 // Order of subscription across patterns
+}
+
+// topicSeq orders topic subscriptions across managers.
+var topicSeq atomic.Uint64
+
+// topics is the topic routing state of a manager.
+type topics struct {
+	mu   sync.RWMutex
+	root topicNode
+}
+
+func (m *manager) SubscribeTopic(pattern string, priority int, fn func(payload any)) (unsubscribe func()) {
+	ts := &topicSub{
+		sub: &subscriber{
+			priority: priority,
+			fn:       func(e Event) { fn(e) },
+			handler:  fn,
+		},
+		exact: !strings.Contains(pattern, topicAnyOne),
+		seq:   topicSeq.Add(1),
+	}
+	segments := strings.Split(pattern, ".")
+
+	m.topics.mu.Lock()
+	node := &m.topics.root
+	for _, seg := range segments {
+		if node.children == nil {
+			node.children = make(map[string]*topicNode)
+		}
+		child, ok := node.children[seg]
+		if !ok {
+			child = new(topicNode)
+			node.children[seg] = child
+		}
+		node = child
+	}
+	subs := make([]*topicSub, 0, len(node.subs)+1)
+	node.subs = append(append(subs, node.subs...), ts)
+	m.topics.mu.Unlock()
+	m.subscribed.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.topics.mu.Lock()
+			defer m.topics.mu.Unlock()
+			subs := make([]*topicSub, 0, len(node.subs))
+			for _, s := range node.subs {
+				if s != ts {
+					subs = append(subs, s)
+				}
+			}
+			if len(subs) != len(node.subs) {
+				m.unsubscribed.Add(1)
+			}
+			node.subs = subs
+		})
+	}
+}
+
+func (m *manager) FireTopic(topic string, payload any) {
+	if m.rejectClosed(payload) {
+		return
+	}
+	m.enter()
+	defer m.exit()
+
+	m.topics.mu.RLock()
+	matched := m.topics.root.match(strings.Split(topic, "."), nil)
+	m.topics.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.sub.priority != b.sub.priority {
+			return a.sub.priority > b.sub.priority
+		}
+		if a.exact != b.exact {
+			return a.exact
+		}
+		return a.seq < b.seq
+	})
+	for _, ts := range matched {
+		m.callSubscriber(ts.sub, payload, nil)
+	}
+}
+
+// match appends the subscribers of all patterns below n matching the segments to matched.
+// The caller must hold the read lock of the topics.
+func (n *topicNode) match(segments []string, matched []*topicSub) []*topicSub {
+	if len(segments) == 0 {
+		return append(matched, n.subs...)
+	}
+	if child, ok := n.children[topicAnyMany]; ok {
+		matched = append(matched, child.subs...)
+	}
+	if seg := segments[0]; seg != topicAnyOne && seg != topicAnyMany {
+		if child, ok := n.children[seg]; ok {
+			matched = child.match(segments[1:], matched)
+		}
+	}
+	if child, ok := n.children[topicAnyOne]; ok {
+		matched = child.match(segments[1:], matched)
+	}
+	return matched
+}
+
+// count returns the number of subscribers of all patterns below n.
+// The caller must hold the read lock of the topics.
+func (n *topicNode) count() int {
+	c := len(n.subs)
+	for _, child := range n.children {
+		c += child.count()
+	}
+	return c
+}
+
+// clear removes all topic subscribers and returns their number. Subscribers are removed from
+// the detached nodes as well, so unsubscribing them later does nothing.
+func (t *topics) clear() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := t.root.clear()
+	t.root = topicNode{}
+	return n
+}
+
+// clear removes the subscribers of all patterns below n and returns their number.
+// The caller must hold the lock of the topics.
+func (n *topicNode) clear() int {
+	c := len(n.subs)
+	n.subs = nil
+	for _, child := range n.children {
+		c += child.clear()
+	}
+	return c
+}
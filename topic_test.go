@@ -0,0 +1,81 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopics(t *testing.T) {
+	m := New()
+	var called []string
+	sub := func(pattern string, priority int) func() {
+		return m.SubscribeTopic(pattern, priority, func(payload any) {
+			called = append(called, pattern+"="+payload.(string))
+		})
+	}
+	sub("user.*", 0)
+	sub("user.created", 0)
+	sub("user.**", 1)
+	sub("*.created", 0)
+	unsubscribe := sub("order.created", 0)
+
+	m.FireTopic("user.created", "a")
+	require.Equal(t, []string{
+		"user.**=a",      // Higher priority first
+		"user.created=a", // Exact before wildcard patterns
+		"user.*=a",
+		"*.created=a",
+	}, called)
+
+	called = nil
+	m.FireTopic("user.profile.updated", "b")
+	require.Equal(t, []string{"user.**=b"}, called)
+
+	called = nil
+	m.FireTopic("user", "c")
+	require.Empty(t, called)
+
+	m.FireTopic("order.created", "d")
+	unsubscribe()
+	unsubscribe()
+	m.FireTopic("order.created", "e")
+	require.Equal(t, []string{"order.created=d", "*.created=d", "*.created=e"}, called)
+}
+
+func TestTopicPanic(t *testing.T) {
+	m := New()
+	var called int
+	m.SubscribeTopic("a", 1, func(any) { panic("boom") })
+	m.SubscribeTopic("a", 0, func(any) { called++ })
+	m.FireTopic("a", nil)
+	require.Equal(t, 1, called)
+}
+
+func TestTopicReset(t *testing.T) {
+	m := New()
+	var called int
+	m.SubscribeTopic("a", 0, func(any) { called++ })
+	m.Reset()
+	m.FireTopic("a", nil)
+	require.Zero(t, called)
+}
+
+func TestTopicUnsubscribeAll(t *testing.T) {
+	m := New()
+	var called int
+	unsubscribe := m.SubscribeTopic("a.b", 0, func(any) { called++ })
+	m.SubscribeTopic("a.*", 0, func(any) { called++ })
+	Subscribe(m, 0, func(*myEvent) {})
+
+	require.Equal(t, 1, m.UnsubscribeAll(&myEvent{}, &baseEvent{})) // Topics are kept for specific events
+	require.Equal(t, 2, m.SubscriberCount())
+	require.Equal(t, 2, m.UnsubscribeAll())
+	require.Zero(t, m.SubscriberCount())
+	m.FireTopic("a.b", nil)
+	require.Zero(t, called)
+
+	unsubscribe() // Does nothing after UnsubscribeAll
+	_, unsubscribed := m.RegistrationStats()
+	require.EqualValues(t, 3, unsubscribed)
+}
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+type tracerKey struct{}
+
+// tracer collects the events of a cascade started by FireTrace.
+type tracer struct {
+	mu     sync.Mutex // Protects following fields
+	events []Event    // Traced events in order of firing
+	done   bool       // Whether FireTrace returned
+}
+
+// FireTrace fires an event like FireCtx and returns it followed by every event fired as a result
+// in order of firing, including nested ones, e.g. to debug event chains. Subscribers take part in
+// the cascade by firing with the context they receive by SubscribeCtx, or a context derived from it,
+// using FireCtx or FireTrace.
+//
+// Events fired asynchronously, e.g. by FireParallelChanCtx, are only included if they are dispatched
+// before FireTrace returns. Managers not created by New only return the event.
+func FireTrace(mgr Publisher, event Event) []Event {
+	t := new(tracer)
+	if _, ok := asManager(mgr); !ok {
+		t.events = append(t.events, event)
+	}
+	mgr.FireCtx(context.WithValue(context.Background(), tracerKey{}, t), event)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = true
+	return t.events
+}
+
+// trace records the event if the fire is part of a cascade started by FireTrace.
+func trace(event Event, opts *fireOptions) {
+	if opts == nil || opts.ctx == nil {
+		return
+	}
+	t, ok := opts.ctx.Value(tracerKey{}).(*tracer)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	if !t.done {
+		t.events = append(t.events, event)
+	}
+	t.mu.Unlock()
+}
+
+// SubscribeCtx is like Subscribe for a handler also receiving the context of the fire,
+// which is set by FireCtx, FireTrace, FireParallelChanCtx and Request and is context.Background()
+// for all other fires. See Subscribe for more details.
+// Managers not created by New always pass context.Background().
+func SubscribeCtx[T Event](mgr Subscriber, priority int, handler func(context.Context, T)) (unsubscribe func()) {
+	var typ T
+	return subscribe(mgr, typ, &subscriber{
+		priority: priority,
+		fn: func(e Event) {
+			if ev, ok := e.(T); ok {
+				handler(context.Background(), ev)
+			}
+		},
+		ctxFn: func(ctx context.Context, e Event) {
+			if ev, ok := e.(T); ok {
+				handler(ctx, ev)
+			}
+		},
+		handler: handler,
+	})
+}
+
+// context returns the context of the fire.
+func (o *fireOptions) context() context.Context {
+	if o == nil || o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
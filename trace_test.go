@@ -0,0 +1,38 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFireTrace(t *testing.T) {
+	m := New()
+	root, child, grandchild, untraced := &myEvent{s: "root"}, &baseEvent{ID: 1}, &myEvent{s: "grandchild"}, &baseEvent{ID: 2}
+	SubscribeCtx(m, 0, func(ctx context.Context, e *myEvent) {
+		if e == root {
+			m.FireCtx(ctx, child)
+			m.Fire(untraced) // Without the context
+		}
+	})
+	SubscribeCtx(m, 0, func(ctx context.Context, e *baseEvent) {
+		if e == child {
+			m.FireCtx(ctx, grandchild)
+		}
+	})
+
+	require.Equal(t, []Event{root, child, grandchild}, FireTrace(m, root))
+	require.Equal(t, []Event{child, grandchild}, FireTrace(m, child))
+	require.Equal(t, []Event{root}, FireTrace(Nop, root))
+}
+
+func TestSubscribeCtx(t *testing.T) {
+	m := New()
+	type key struct{}
+	var values []any
+	SubscribeCtx(m, 0, func(ctx context.Context, _ *myEvent) { values = append(values, ctx.Value(key{})) })
+	m.FireCtx(context.WithValue(context.Background(), key{}, "v"), &myEvent{})
+	m.Fire(&myEvent{})
+	require.Equal(t, []any{"v", nil}, values)
+}